@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/app"
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	"github.com/n0f4ph4mst3r/goshort/internal/transfer"
+	"github.com/n0f4ph4mst3r/goshort/internal/urlnorm"
+)
+
+const usage = `usage:
+  goshort                         run the server
+  goshort export [flags]          write all links to a file or stdout
+  goshort import [flags] FILE     load links from FILE, or stdin if FILE is -
+  goshort key add-user NAME       create a user
+  goshort key issue [flags] USER  issue an API key and print it once
+  goshort key list USER           list the API keys of a user
+  goshort key revoke ID           revoke an API key`
+
+// runCommand runs a subcommand of the binary and returns its exit code.
+// Logs go to stderr so that exports can be piped from stdout.
+func runCommand(name string, args []string) int {
+	switch name {
+	case "export":
+		return runExport(args)
+	case "import":
+		return runImport(args)
+	case "key":
+		return runKey(args)
+	case "help", "-h", "-help", "--help":
+		fmt.Println(usage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s\n", name, usage)
+		return 2
+	}
+}
+
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", string(transfer.FormatNDJSON), "output format: csv or ndjson")
+	output := fs.String("o", "-", "output file, - for stdout")
+	_ = fs.Parse(args)
+
+	f, err := transfer.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	links, err := setupTransfer()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to initialize storage:", err)
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+
+	n, err := links.Export(context.Background(), w, f)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "export failed:", err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d links\n", n)
+	return 0
+}
+
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", string(transfer.FormatNDJSON), "input format: csv or ndjson")
+	strategy := fs.String("strategy", string(transfer.StrategySkip), "what to do with taken aliases: skip, overwrite or fail")
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without writing anything")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	f, err := transfer.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	st, err := transfer.ParseStrategy(*strategy)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var r io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		r = file
+	}
+
+	links, err := setupTransfer()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to initialize storage:", err)
+		return 1
+	}
+
+	report, err := links.Import(context.Background(), r, f, transfer.ImportOptions{Strategy: st, DryRun: *dryRun})
+	if err != nil && !errors.Is(err, transfer.ErrConflict) {
+		fmt.Fprintln(os.Stderr, "import failed:", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "import aborted:", err)
+		return 1
+	}
+	return 0
+}
+
+func runKey(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("key "+args[0], flag.ExitOnError)
+	name := fs.String("name", "", "label of the issued key")
+	scopes := fs.String("scopes", "", "space or comma separated scopes of the issued key: links:read, links:write, links:delete or admin (default all links scopes)")
+	_ = fs.Parse(args[1:])
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	arg := fs.Arg(0)
+
+	_, _, keys, err := setupBackend()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to initialize storage:", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	var out any
+	switch args[0] {
+	case "add-user":
+		out, err = keys.CreateUser(ctx, arg)
+	case "issue":
+		var plain string
+		var key storage.APIKey
+		plain, key, err = auth.Issue(ctx, keys, arg, *name, strings.Split(*scopes, ","))
+		out = struct {
+			Key    string         `json:"key"`
+			APIKey storage.APIKey `json:"api_key"`
+		}{plain, key}
+	case "list":
+		var user storage.User
+		if user, err = keys.GetUser(ctx, arg); err == nil {
+			out, err = keys.ListAPIKeys(ctx, user.ID)
+		}
+	case "revoke":
+		id, perr := strconv.ParseInt(arg, 10, 64)
+		if perr != nil {
+			fmt.Fprintln(os.Stderr, "invalid key id:", arg)
+			return 2
+		}
+		err = keys.RevokeAPIKey(ctx, id)
+		out = map[string]int64{"revoked": id}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown key command %q\n%s\n", args[0], usage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(out)
+
+	return 0
+}
+
+// setupTransfer opens storage for export and import. Imports from the
+// command line are run by the operator, so destinations are canonicalized
+// but not vetted by the destination policy or the blocklist.
+func setupTransfer() (*transfer.Transfer, error) {
+	cfg, url_storage, _, err := setupBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer.New(url_storage, urlnorm.New(&cfg.URL), nil), nil
+}
+
+// setupBackend loads the config and opens storage the way the server does,
+// logging to stderr.
+func setupBackend() (*config.Config, *storage.UrlStorage, storage.Backend, error) {
+	cfg, dbUrl, cacheUrl := config.MustLoad()
+	log := setupLogger(cfg.Env, os.Stderr)
+
+	url_storage, backend, err := app.NewStorage(log, cfg, dbUrl, cacheUrl)
+	return cfg, url_storage, backend, err
+}
@@ -1,23 +1,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
-
+	"github.com/n0f4ph4mst3r/goshort/internal/app"
 	"github.com/n0f4ph4mst3r/goshort/internal/config"
-	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/erase"
-	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/redirect"
-	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/save"
-	"github.com/n0f4ph4mst3r/goshort/internal/http-server/mwlogger"
-	"github.com/n0f4ph4mst3r/goshort/internal/storage"
-	"github.com/n0f4ph4mst3r/goshort/internal/storage/postgres"
-	rds "github.com/n0f4ph4mst3r/goshort/internal/storage/redis"
 )
 
 const (
@@ -27,6 +22,10 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
 	dir, err := os.Getwd()
 	if err != nil {
 		log.Fatal(err)
@@ -34,70 +33,73 @@ func main() {
 	fmt.Println("PWD:", dir)
 
 	cfg, dbUrl, cacheUrl := config.MustLoad()
-	log := setupLogger(cfg.Env)
+	log := setupLogger(cfg.Env, os.Stdout)
 
 	log.Info("Starting application...", slog.String("env", cfg.Env))
 	log.Debug("Debugging is enabled")
 
-	postgres, err := postgres.New(dbUrl)
+	application, err := app.New(log, cfg, dbUrl, cacheUrl)
 	if err != nil {
-		log.Error("Failed to initialize storage", "err", err)
+		log.Error("Failed to initialize application", "err", err)
 		os.Exit(1)
 	}
 
-	rdsStorage, err := rds.New(cacheUrl, &cfg.Cache)
-	if err != nil {
-		log.Warn(err.Error())
-	}
-
-	url_storage := storage.New(log, postgres, rdsStorage)
-
-	router := chi.NewRouter()
-	router.Use(middleware.RequestID)
-	router.Use(middleware.Logger)
-	router.Use(mwlogger.New(log))
-	router.Use(middleware.Recoverer)
-	router.Use(middleware.URLFormat)
-
-	router.Route("/api", func(api_routes chi.Router) {
-		api_routes.Get("/url/{alias}", redirect.New(log, url_storage))
-
-		api_routes.Route("/url", func(auth_routes chi.Router) {
-			auth_routes.Use(middleware.BasicAuth("goshort", map[string]string{
-				cfg.HTTPServer.User: cfg.HTTPServer.Password,
-			}))
-
-			auth_routes.Post("/", save.New(log, url_storage, nil))
-			auth_routes.Delete("/{alias}", erase.New(log, url_storage))
-		})
-	})
-
 	log.Info("starting server", slog.String("address", cfg.Address+":"+fmt.Sprint(cfg.Port)))
 
 	srv := &http.Server{
 		Addr:         cfg.Address + ":" + fmt.Sprint(cfg.Port),
-		Handler:      router,
+		Handler:      application.Handler,
 		ReadTimeout:  cfg.HTTPServer.Timeout,
 		WriteTimeout: cfg.HTTPServer.Timeout,
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
 
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serverErr <- err
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	exitCode := 0
+	select {
+	case err := <-serverErr:
 		log.Error("failed to start server", slog.Any("err", err))
-		os.Exit(1)
+		exitCode = 1
+	case sig := <-stop:
+		log.Info("stopping server", slog.String("signal", sig.String()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error("failed to stop server", slog.Any("err", err))
+	}
+
+	if err := application.Close(ctx); err != nil {
+		log.Error("failed to stop application", slog.Any("err", err))
+	}
+
+	log.Info("server stopped")
+	if exitCode != 0 {
+		os.Exit(exitCode)
 	}
 }
 
-func setupLogger(env string) *slog.Logger {
+func setupLogger(env string, w io.Writer) *slog.Logger {
 	var log *slog.Logger
 
 	switch env {
 	case envLocal:
-		log = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		log = slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	case envDev:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		log = slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	case envProd:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		log = slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	}
 
 	return log
@@ -0,0 +1,174 @@
+package alias
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+)
+
+var (
+	ErrLength   = errors.New("alias length is out of range")
+	ErrCharset  = errors.New("alias contains characters that are not allowed")
+	ErrReserved = errors.New("alias is a reserved word")
+	ErrBlocked  = errors.New("alias contains a blocked word")
+)
+
+// leet folds common digit substitutions so that blocked words cannot be
+// smuggled in by swapping letters for look-alike digits.
+var leet = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+// Policy decides which aliases are acceptable, both for user-supplied and
+// for generated aliases.
+//
+// The zero Policy, as well as a nil *Policy, accepts every alias.
+type Policy struct {
+	charset   map[rune]struct{}
+	minLength int
+	maxLength int
+	reserved  map[string]struct{}
+	blocked   []string
+}
+
+func NewPolicy(cfg *config.AliasPolicyConfig) (*Policy, error) {
+	const op = "alias.NewPolicy"
+
+	p := &Policy{
+		charset:   make(map[rune]struct{}),
+		minLength: cfg.MinLength,
+		maxLength: cfg.MaxLength,
+		reserved:  make(map[string]struct{}),
+	}
+
+	for _, r := range cfg.Charset {
+		p.charset[r] = struct{}{}
+	}
+	for _, word := range cfg.Reserved {
+		if word = strings.TrimSpace(word); word != "" {
+			p.reserved[strings.ToLower(word)] = struct{}{}
+		}
+	}
+
+	if cfg.BlocklistFile != "" {
+		words, err := readWords(cfg.BlocklistFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for _, word := range words {
+			p.blocked = append(p.blocked, leet.Replace(strings.ToLower(word)))
+		}
+	}
+
+	return p, nil
+}
+
+// Check reports the first rule the alias violates, or nil.
+func (p *Policy) Check(alias string) error {
+	if p == nil {
+		return nil
+	}
+	if n := utf8.RuneCountInString(alias); n < p.minLength || (p.maxLength > 0 && n > p.maxLength) {
+		return ErrLength
+	}
+	if !p.validCharset(alias) {
+		return ErrCharset
+	}
+	if p.isReserved(alias) {
+		return ErrReserved
+	}
+	if p.isBlocked(alias) {
+		return ErrBlocked
+	}
+	return nil
+}
+
+// Register adds the "alias" tag to v. The tag expands into min/max and the
+// alias_charset, alias_reserved and alias_blocked rules, so a violation is
+// reported as a regular validator.FieldError naming the broken rule.
+func (p *Policy) Register(v *validator.Validate) error {
+	const op = "alias.Policy.Register"
+
+	if p == nil {
+		p = &Policy{}
+	}
+
+	rules := map[string]func(string) bool{
+		"alias_charset":  p.validCharset,
+		"alias_reserved": func(s string) bool { return !p.isReserved(s) },
+		"alias_blocked":  func(s string) bool { return !p.isBlocked(s) },
+	}
+	for tag, rule := range rules {
+		err := v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			return rule(fl.Field().String())
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	tags := []string{fmt.Sprintf("min=%d", p.minLength)}
+	if p.maxLength > 0 {
+		tags = append(tags, fmt.Sprintf("max=%d", p.maxLength))
+	}
+	tags = append(tags, "alias_charset", "alias_reserved", "alias_blocked")
+	v.RegisterAlias("alias", strings.Join(tags, ","))
+
+	return nil
+}
+
+func (p *Policy) validCharset(alias string) bool {
+	if len(p.charset) == 0 {
+		return true
+	}
+	for _, r := range alias {
+		if _, ok := p.charset[r]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Policy) isReserved(alias string) bool {
+	_, ok := p.reserved[strings.ToLower(alias)]
+	return ok
+}
+
+func (p *Policy) isBlocked(alias string) bool {
+	folded := leet.Replace(strings.ToLower(alias))
+	for _, word := range p.blocked {
+		if strings.Contains(folded, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// readWords reads one word per line, skipping blank lines and # comments.
+func readWords(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
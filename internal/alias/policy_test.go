@@ -0,0 +1,62 @@
+package alias_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/alias"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	blocklist := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(blocklist, []byte("# comment\n\nbadword\n  evil  \n"), 0o600))
+
+	policy, err := alias.NewPolicy(&config.AliasPolicyConfig{
+		Charset:       "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_",
+		MinLength:     3,
+		MaxLength:     8,
+		Reserved:      []string{"api", " Admin "},
+		BlocklistFile: blocklist,
+	})
+	require.NoError(t, err)
+
+	cases := []struct {
+		alias string
+		err   error
+	}{
+		{"go-duck", nil},
+		{"ab", alias.ErrLength},
+		{"abcdefghi", alias.ErrLength},
+		{"go.duck", alias.ErrCharset},
+		{"gö-duck", alias.ErrCharset},
+		{"API", alias.ErrReserved},
+		{"admin", alias.ErrReserved},
+		{"apis", nil},
+		{"xBADWORD", alias.ErrBlocked},
+		{"b4dw0rd", alias.ErrBlocked},
+		{"3v1l", alias.ErrBlocked},
+	}
+
+	for _, tc := range cases {
+		require.ErrorIs(t, policy.Check(tc.alias), tc.err, tc.alias)
+		if tc.err == nil {
+			require.NoError(t, policy.Check(tc.alias), tc.alias)
+		}
+	}
+}
+
+func TestPolicy_Nil(t *testing.T) {
+	var policy *alias.Policy
+	require.NoError(t, policy.Check("any thing/at all"))
+}
+
+func TestPolicy_MissingBlocklist(t *testing.T) {
+	_, err := alias.NewPolicy(&config.AliasPolicyConfig{
+		BlocklistFile: filepath.Join(t.TempDir(), "missing.txt"),
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,202 @@
+package alias
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+)
+
+const (
+	Base62 = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	// Unambiguous is a lower-case alphabet without the characters people
+	// confuse when reading an alias aloud or off paper: 0/o, 1/l/i.
+	Unambiguous = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	// maxBlock bounds the size of a block so that block sums fit in an
+	// int64 and the Feistel halves stay within 30 bits each.
+	maxBlock    = 1 << 60
+	feistelRuns = 4
+)
+
+var (
+	ErrExhausted   = errors.New("alias sequence exhausted")
+	ErrInvalidCode = errors.New("invalid alias code")
+)
+
+type SequenceSource interface {
+	NextSequence(ctx context.Context) (int64, error)
+}
+
+// Sequence derives aliases from a monotonically increasing sequence.
+// Sequence values are split into consecutive blocks: with an alphabet of b
+// characters the first b^MinLength values map to codes of MinLength
+// characters, the next b^(MinLength+1) to codes one character longer, and
+// so on, so codes grow only when a length is used up. Within a block the
+// index is shuffled by a keyed Feistel permutation when a secret is
+// configured, which keeps codes collision-free while making consecutive
+// codes unrelated.
+type Sequence struct {
+	source    SequenceSource
+	alphabet  string
+	minLength int
+	maxLength int
+	key       []byte
+}
+
+// NewSequence builds a generator over the base62 alphabet, or over the
+// Unambiguous one when aliases are case-insensitive.
+func NewSequence(source SequenceSource, cfg *config.AliasConfig) *Sequence {
+	g := &Sequence{source: source, alphabet: Base62}
+	if cfg.CaseInsensitive {
+		g.alphabet = Unambiguous
+	}
+
+	// maxLength is the longest code whose block stays below maxBlock.
+	base := uint64(len(g.alphabet))
+	for size := uint64(1); size <= maxBlock/base; size *= base {
+		g.maxLength++
+	}
+	g.minLength = min(max(cfg.MinLength, 1), g.maxLength)
+
+	if cfg.Secret != "" {
+		g.key = []byte(cfg.Secret)
+	}
+
+	return g
+}
+
+func (g *Sequence) Generate(ctx context.Context) (string, error) {
+	const op = "alias.Sequence.Generate"
+
+	n, err := g.source.NextSequence(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	code, err := g.Encode(n)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return code, nil
+}
+
+// Encode maps a sequence value (starting at 1) to its code.
+func (g *Sequence) Encode(n int64) (string, error) {
+	if n < 1 {
+		return "", ErrInvalidCode
+	}
+
+	idx := uint64(n - 1)
+	for length := g.minLength; length <= g.maxLength; length++ {
+		size := g.blockSize(length)
+		if idx < size {
+			return g.format(g.permute(idx, size, length, false), length), nil
+		}
+		idx -= size
+	}
+
+	return "", ErrExhausted
+}
+
+// Decode is the inverse of Encode.
+func (g *Sequence) Decode(code string) (int64, error) {
+	length := len(code)
+	if length < g.minLength || length > g.maxLength {
+		return 0, ErrInvalidCode
+	}
+
+	var idx uint64
+	for i := 0; i < length; i++ {
+		d := strings.IndexByte(g.alphabet, code[i])
+		if d < 0 {
+			return 0, ErrInvalidCode
+		}
+		idx = idx*uint64(len(g.alphabet)) + uint64(d)
+	}
+
+	size := g.blockSize(length)
+	n := g.permute(idx, size, length, true)
+	for l := g.minLength; l < length; l++ {
+		n += g.blockSize(l)
+	}
+
+	return int64(n) + 1, nil
+}
+
+func (g *Sequence) blockSize(length int) uint64 {
+	size := uint64(1)
+	for range length {
+		size *= uint64(len(g.alphabet))
+	}
+	return size
+}
+
+func (g *Sequence) format(idx uint64, length int) string {
+	base := uint64(len(g.alphabet))
+	buf := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		buf[i] = g.alphabet[idx%base]
+		idx /= base
+	}
+	return string(buf)
+}
+
+// permute applies a Feistel network over the smallest even number of bits
+// covering size and walks the cycle until the result falls inside the
+// block, which yields a bijection on [0, size).
+func (g *Sequence) permute(idx, size uint64, length int, inverse bool) uint64 {
+	if g.key == nil {
+		return idx
+	}
+
+	width := bits.Len64(size - 1)
+	width += width % 2
+	half := uint(width / 2)
+
+	for {
+		idx = g.feistel(idx, half, length, inverse)
+		if idx < size {
+			return idx
+		}
+	}
+}
+
+func (g *Sequence) feistel(v uint64, half uint, length int, inverse bool) uint64 {
+	mask := uint64(1)<<half - 1
+	left, right := v>>half, v&mask
+
+	for i := range feistelRuns {
+		round := i
+		if inverse {
+			round = feistelRuns - 1 - i
+		}
+
+		if inverse {
+			left, right = right^g.round(left, round, length)&mask, left
+		} else {
+			left, right = right, left^g.round(right, round, length)&mask
+		}
+	}
+
+	return left<<half | right
+}
+
+func (g *Sequence) round(v uint64, round, length int) uint64 {
+	var buf [10]byte
+	binary.BigEndian.PutUint64(buf[:8], v)
+	buf[8] = byte(round)
+	buf[9] = byte(length)
+
+	mac := hmac.New(sha256.New, g.key)
+	mac.Write(buf[:])
+	return binary.BigEndian.Uint64(mac.Sum(nil))
+}
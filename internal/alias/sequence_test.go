@@ -0,0 +1,128 @@
+package alias_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/alias"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+)
+
+type counter struct {
+	n   int64
+	err error
+}
+
+func (c *counter) NextSequence(_ context.Context) (int64, error) {
+	c.n++
+	return c.n, c.err
+}
+
+func TestSequence_PlainCodes(t *testing.T) {
+	gen := alias.NewSequence(&counter{}, &config.AliasConfig{MinLength: 2})
+
+	cases := []struct {
+		n    int64
+		code string
+	}{
+		{1, "aa"},
+		{2, "ab"},
+		{63, "ba"},
+		{62 * 62, "99"},
+		{62*62 + 1, "aaa"},
+	}
+
+	for _, tc := range cases {
+		code, err := gen.Encode(tc.n)
+		require.NoError(t, err)
+		require.Equal(t, tc.code, code)
+
+		n, err := gen.Decode(code)
+		require.NoError(t, err)
+		require.Equal(t, tc.n, n)
+	}
+}
+
+func TestSequence_ObfuscatedCodesAreUniqueAndReversible(t *testing.T) {
+	gen := alias.NewSequence(&counter{}, &config.AliasConfig{MinLength: 2, Secret: "secret"})
+
+	seen := make(map[string]struct{})
+	total := int64(62*62 + 500)
+	for n := int64(1); n <= total; n++ {
+		code, err := gen.Encode(n)
+		require.NoError(t, err)
+
+		if n <= 62*62 {
+			require.Len(t, code, 2)
+		} else {
+			require.Len(t, code, 3)
+		}
+
+		_, dup := seen[code]
+		require.False(t, dup, "duplicate code %q for %d", code, n)
+		seen[code] = struct{}{}
+
+		back, err := gen.Decode(code)
+		require.NoError(t, err)
+		require.Equal(t, n, back)
+	}
+
+	plain := alias.NewSequence(&counter{}, &config.AliasConfig{MinLength: 2})
+	first, _ := gen.Encode(1)
+	second, _ := gen.Encode(2)
+	plainFirst, _ := plain.Encode(1)
+	require.NotEqual(t, plainFirst, first)
+	require.NotEqual(t, first, second)
+}
+
+func TestSequence_Generate(t *testing.T) {
+	gen := alias.NewSequence(&counter{}, &config.AliasConfig{MinLength: 6, Secret: "secret"})
+
+	code, err := gen.Generate(context.Background())
+	require.NoError(t, err)
+	require.Len(t, code, 6)
+
+	failing := alias.NewSequence(&counter{err: errors.New("db is down")}, &config.AliasConfig{MinLength: 6})
+	_, err = failing.Generate(context.Background())
+	require.Error(t, err)
+}
+
+func TestSequence_Decode_Invalid(t *testing.T) {
+	gen := alias.NewSequence(&counter{}, &config.AliasConfig{MinLength: 3})
+
+	for _, code := range []string{"", "ab", "ab-", "abcdefghijk"} {
+		_, err := gen.Decode(code)
+		require.ErrorIs(t, err, alias.ErrInvalidCode)
+	}
+}
+
+func TestSequence_CaseInsensitiveAlphabet(t *testing.T) {
+	gen := alias.NewSequence(&counter{}, &config.AliasConfig{MinLength: 2, Secret: "secret", CaseInsensitive: true})
+
+	size := int64(len(alias.Unambiguous))
+	seen := make(map[string]struct{})
+	for n := int64(1); n <= size*size+100; n++ {
+		code, err := gen.Encode(n)
+		require.NoError(t, err)
+		for _, c := range code {
+			require.Contains(t, alias.Unambiguous, string(c))
+		}
+
+		_, dup := seen[code]
+		require.False(t, dup, "duplicate code %q for %d", code, n)
+		seen[code] = struct{}{}
+
+		back, err := gen.Decode(code)
+		require.NoError(t, err)
+		require.Equal(t, n, back)
+	}
+
+	// Twelve characters is the longest block of the 31-character alphabet.
+	_, err := gen.Decode("aaaaaaaaaaaaa")
+	require.ErrorIs(t, err, alias.ErrInvalidCode)
+	_, err = gen.Decode("aaaaaaaaaaaa")
+	require.NoError(t, err)
+}
@@ -0,0 +1,30 @@
+package alias
+
+import "strconv"
+
+// suffixes are short words appended to or prepended before a vanity alias.
+var suffixes = []string{"go", "app", "hq", "now", "link"}
+
+// Variants returns candidate aliases derived from base, starting with base
+// itself: numeric tails with and without separators, word suffixes and
+// prefixes, then two-digit tails. The order is stable, so the first
+// available candidates are always the shortest and most natural ones.
+func Variants(base string) []string {
+	variants := []string{base}
+
+	for i := 1; i <= 9; i++ {
+		n := strconv.Itoa(i)
+		variants = append(variants, base+n, base+"-"+n, base+"_"+n)
+
+		if i <= len(suffixes) {
+			word := suffixes[i-1]
+			variants = append(variants, base+"-"+word, word+"-"+base)
+		}
+	}
+
+	for i := 10; i <= 99; i++ {
+		variants = append(variants, base+"-"+strconv.Itoa(i))
+	}
+
+	return variants
+}
@@ -0,0 +1,166 @@
+package analytics
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/clientip"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+const flushTimeout = 5 * time.Second
+
+type ClickSaver interface {
+	SaveClicks(ctx context.Context, clicks []storage.Click) error
+}
+
+// Recorder collects click events off the request path. Events are buffered
+// in a channel and written to storage in batches by a single background
+// goroutine, either when a batch fills up or when the flush interval passes.
+// When the buffer is full new events are dropped rather than blocking the
+// redirect.
+//
+// A nil *Recorder is valid and records nothing.
+type Recorder struct {
+	log    *slog.Logger
+	saver  ClickSaver
+	cfg    *config.AnalyticsConfig
+	ips    *clientip.Resolver
+	events chan storage.Click
+	done   chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewRecorder returns a running Recorder. Clicks are attributed to the
+// client IP that ips resolves, so that requests relayed by a trusted proxy
+// are not all counted as one visitor.
+func NewRecorder(log *slog.Logger, saver ClickSaver, cfg *config.AnalyticsConfig, ips *clientip.Resolver) *Recorder {
+	r := &Recorder{
+		log:    log.With(slog.String("component", "analytics/recorder")),
+		saver:  saver,
+		cfg:    cfg,
+		ips:    ips,
+		events: make(chan storage.Click, cfg.BufferSize),
+		done:   make(chan struct{}),
+	}
+
+	go r.run()
+
+	return r
+}
+
+func (r *Recorder) Record(req *http.Request, alias string) {
+	if r == nil {
+		return
+	}
+
+	click := storage.Click{
+		Alias:     alias,
+		ClickedAt: time.Now(),
+		Referrer:  req.Referer(),
+		UserAgent: req.UserAgent(),
+		IPHash:    r.hashIP(r.ips.IP(req)),
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return
+	}
+
+	select {
+	case r.events <- click:
+	default:
+		r.log.Warn("click buffer is full, dropping event", slog.String("alias", alias))
+	}
+}
+
+// Close stops accepting events and waits until everything buffered so far
+// has been written, or until ctx is done.
+func (r *Recorder) Close(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.events)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	// Without a flush interval batches are written only once full and on
+	// close; a nil channel never fires.
+	var tick <-chan time.Time
+	if r.cfg.FlushInterval > 0 {
+		ticker := time.NewTicker(r.cfg.FlushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	batch := make([]storage.Click, 0, r.cfg.BatchSize)
+	for {
+		select {
+		case click, ok := <-r.events:
+			if !ok {
+				r.flush(batch)
+				return
+			}
+
+			batch = append(batch, click)
+			if len(batch) >= r.cfg.BatchSize {
+				r.flush(batch)
+				batch = batch[:0]
+			}
+		case <-tick:
+			r.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (r *Recorder) flush(batch []storage.Click) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	if err := r.saver.SaveClicks(ctx, batch); err != nil {
+		r.log.Error("failed to save clicks", slog.Int("count", len(batch)), sl.Err(err))
+		return
+	}
+
+	r.log.Debug("clicks saved", slog.Int("count", len(batch)))
+}
+
+func (r *Recorder) hashIP(ip string) string {
+	if ip == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(r.cfg.IPSalt + ip))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,119 @@
+package analytics_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/analytics"
+	"github.com/n0f4ph4mst3r/goshort/internal/clientip"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+type clickSaver struct {
+	mu      sync.Mutex
+	batches [][]storage.Click
+}
+
+func (s *clickSaver) SaveClicks(_ context.Context, clicks []storage.Click) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batches = append(s.batches, append([]storage.Click(nil), clicks...))
+	return nil
+}
+
+func TestRecorder_FlushesOnClose(t *testing.T) {
+	saver := &clickSaver{}
+	recorder := analytics.NewRecorder(sldiscard.NewDiscardLogger(), saver, &config.AnalyticsConfig{
+		BufferSize:    10,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		IPSalt:        "salt",
+	}, nil)
+
+	for range 3 {
+		req := httptest.NewRequest("GET", "/api/url/GoDuck", nil)
+		req.Header.Set("Referer", "https://example.com")
+		req.Header.Set("User-Agent", "test-agent")
+		req.RemoteAddr = "192.0.2.1:1234"
+
+		recorder.Record(req, "GoDuck")
+	}
+
+	require.NoError(t, recorder.Close(context.Background()))
+
+	recorder.Record(httptest.NewRequest("GET", "/api/url/GoDuck", nil), "GoDuck")
+
+	var clicks []storage.Click
+	for _, batch := range saver.batches {
+		require.LessOrEqual(t, len(batch), 2)
+		clicks = append(clicks, batch...)
+	}
+	require.Len(t, clicks, 3)
+
+	for _, c := range clicks {
+		require.Equal(t, "GoDuck", c.Alias)
+		require.Equal(t, "https://example.com", c.Referrer)
+		require.Equal(t, "test-agent", c.UserAgent)
+		require.NotEmpty(t, c.IPHash)
+		require.NotContains(t, c.IPHash, "192.0.2.1")
+		require.False(t, c.ClickedAt.IsZero())
+	}
+}
+
+func TestRecorder_TrustedProxy(t *testing.T) {
+	ips, err := clientip.New([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	saver := &clickSaver{}
+	recorder := analytics.NewRecorder(sldiscard.NewDiscardLogger(), saver, &config.AnalyticsConfig{
+		BufferSize: 10,
+		BatchSize:  10,
+		IPSalt:     "salt",
+	}, ips)
+
+	for _, forwarded := range []string{"192.0.2.1", "198.51.100.7"} {
+		req := httptest.NewRequest("GET", "/api/url/GoDuck", nil)
+		req.RemoteAddr = "10.0.0.2:1234"
+		req.Header.Set("X-Forwarded-For", forwarded)
+		recorder.Record(req, "GoDuck")
+	}
+	direct := httptest.NewRequest("GET", "/api/url/GoDuck", nil)
+	direct.RemoteAddr = "192.0.2.1:4321"
+	recorder.Record(direct, "GoDuck")
+
+	require.NoError(t, recorder.Close(context.Background()))
+	require.Len(t, saver.batches, 1)
+
+	clicks := saver.batches[0]
+	require.Len(t, clicks, 3)
+	require.NotEqual(t, clicks[0].IPHash, clicks[1].IPHash)
+	require.Equal(t, clicks[0].IPHash, clicks[2].IPHash)
+}
+
+func TestRecorder_NoFlushInterval(t *testing.T) {
+	saver := &clickSaver{}
+	recorder := analytics.NewRecorder(sldiscard.NewDiscardLogger(), saver, &config.AnalyticsConfig{
+		BufferSize: 10,
+		BatchSize:  5,
+	}, nil)
+
+	recorder.Record(httptest.NewRequest("GET", "/api/url/GoDuck", nil), "GoDuck")
+	require.NoError(t, recorder.Close(context.Background()))
+
+	require.Len(t, saver.batches, 1)
+}
+
+func TestRecorder_NilIsNoop(t *testing.T) {
+	var recorder *analytics.Recorder
+
+	recorder.Record(httptest.NewRequest("GET", "/api/url/GoDuck", nil), "GoDuck")
+	require.NoError(t, recorder.Close(context.Background()))
+}
@@ -0,0 +1,217 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/alias"
+	"github.com/n0f4ph4mst3r/goshort/internal/analytics"
+	"github.com/n0f4ph4mst3r/goshort/internal/clientip"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/destination"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/mwratelimit"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/router"
+	"github.com/n0f4ph4mst3r/goshort/internal/jwt"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage/memory"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage/postgres"
+	rds "github.com/n0f4ph4mst3r/goshort/internal/storage/redis"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage/sqlite"
+	"github.com/n0f4ph4mst3r/goshort/internal/trash"
+)
+
+// App is the HTTP API of goshort together with the background components
+// it runs: click recording, trash purging, blocklist and JWKS refreshes and
+// rate limiting.
+type App struct {
+	Handler http.Handler
+
+	recorder  *analytics.Recorder
+	purger    *trash.Purger
+	blocklist *destination.Blocklist
+	keySet    *jwt.KeySet
+	limits    *mwratelimit.Limits
+}
+
+// New builds the application described by cfg. dbUrl and cacheUrl locate
+// the database and Redis; they are unused with the memory backends.
+func New(log *slog.Logger, cfg *config.Config, dbUrl, cacheUrl string) (*App, error) {
+	const op = "app.New"
+
+	a := &App{}
+	fail := func(msg string, err error) (*App, error) {
+		_ = a.Close(context.Background())
+		return nil, fmt.Errorf("%s: %s: %w", op, msg, err)
+	}
+
+	url_storage, backend, err := NewStorage(log, cfg, dbUrl, cacheUrl)
+	if err != nil {
+		return fail("failed to initialize storage", err)
+	}
+
+	ips, err := clientip.New(cfg.HTTPServer.TrustedProxies)
+	if err != nil {
+		return fail("failed to load trusted proxies", err)
+	}
+
+	if cfg.Analytics.Enabled {
+		a.recorder = analytics.NewRecorder(log, url_storage, &cfg.Analytics, ips)
+	}
+
+	if cfg.Trash.Retention > 0 {
+		a.purger = trash.NewPurger(log, url_storage, &cfg.Trash)
+	}
+
+	policy, err := alias.NewPolicy(&cfg.Alias.Policy)
+	if err != nil {
+		return fail("failed to load alias policy", err)
+	}
+
+	destinations, err := destination.NewPolicy(&cfg.Destination, net.DefaultResolver)
+	if err != nil {
+		return fail("failed to load destination policy", err)
+	}
+
+	if cfg.Destination.Blocklist.DomainsFile != "" || cfg.Destination.Blocklist.HashPrefixFile != "" {
+		a.blocklist, err = destination.NewBlocklist(log, &cfg.Destination.Blocklist)
+		if err != nil {
+			return fail("failed to load blocklist", err)
+		}
+	}
+
+	var tokens *jwt.Verifier
+	switch cfg.Auth.Mode {
+	case config.AuthKeys:
+	case config.AuthJWT:
+		a.keySet, err = jwt.NewKeySet(log, &cfg.Auth.JWT)
+		if err != nil {
+			return fail("failed to load JWKS", err)
+		}
+		tokens = jwt.NewVerifier(a.keySet, &cfg.Auth.JWT)
+	default:
+		return fail("failed to set up authentication", fmt.Errorf("unknown auth mode %q", cfg.Auth.Mode))
+	}
+
+	if cfg.RateLimit.Enabled {
+		a.limits, err = newRateLimits(log, cfg, cacheUrl, ips)
+		if err != nil {
+			return fail("failed to initialize rate limiting", err)
+		}
+	}
+
+	a.Handler = router.New(log, cfg, router.Deps{
+		Links:        url_storage,
+		Keys:         backend,
+		Recorder:     a.recorder,
+		Policy:       policy,
+		Destinations: destinations,
+		Blocklist:    a.blocklist,
+		Tokens:       tokens,
+		Limits:       a.limits,
+	})
+
+	return a, nil
+}
+
+// Close stops the background components, flushing pending click events.
+func (a *App) Close(ctx context.Context) error {
+	var errs []error
+	if err := a.recorder.Close(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush click events: %w", err))
+	}
+	if err := a.purger.Close(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop trash purger: %w", err))
+	}
+	if err := a.blocklist.Close(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop blocklist refresh: %w", err))
+	}
+	if err := a.keySet.Close(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop JWKS refresh: %w", err))
+	}
+	if err := a.limits.Close(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close rate limiter: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// NewStorage opens the configured storage backend and cache and returns
+// the link storage on top of them, along with the backend itself.
+func NewStorage(log *slog.Logger, cfg *config.Config, dbUrl, cacheUrl string) (*storage.UrlStorage, storage.Backend, error) {
+	backend, err := newBackend(cfg, dbUrl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cache, err := newCache(cfg, cacheUrl)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+
+	var opts []storage.Option
+	if cfg.Alias.CaseInsensitive {
+		// Links stored before the option was enabled must stay reachable
+		// under their folded alias; refuse to start if that is ambiguous.
+		if err := backend.FoldAliases(context.Background()); err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, storage.WithCaseInsensitiveAliases())
+	}
+
+	return storage.New(log, backend, cache, opts...), backend, nil
+}
+
+func newBackend(cfg *config.Config, dbUrl string) (storage.Backend, error) {
+	switch cfg.Storage.Backend {
+	case config.StorageMemory:
+		return memory.New(), nil
+	case config.StorageDatabase:
+		if sqlite.IsSQLite(dbUrl) {
+			return sqlite.New(dbUrl)
+		}
+		return postgres.New(dbUrl)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+	}
+}
+
+func newRateLimits(log *slog.Logger, cfg *config.Config, cacheUrl string, ips *clientip.Resolver) (*mwratelimit.Limits, error) {
+	var limiter mwratelimit.Limiter
+	switch cfg.RateLimit.Backend {
+	case config.RateLimitMemory:
+		limiter = memory.NewLimiter()
+	case config.RateLimitRedis:
+		rdsLimiter, err := rds.NewLimiter(cacheUrl, &cfg.RateLimit)
+		if err != nil {
+			return nil, err
+		}
+		limiter = rdsLimiter
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q", cfg.RateLimit.Backend)
+	}
+
+	return mwratelimit.New(log, limiter, &cfg.RateLimit, ips), nil
+}
+
+func newCache(cfg *config.Config, cacheUrl string) (storage.CacheClient, error) {
+	if !cfg.Cache.Enabled {
+		return nil, nil
+	}
+
+	switch cfg.Cache.Backend {
+	case config.CacheMemory:
+		return memory.NewCache(&cfg.Cache), nil
+	case config.CacheRedis:
+		rdsStorage, err := rds.New(cacheUrl, &cfg.Cache)
+		if err != nil {
+			return nil, err
+		}
+		return rdsStorage, nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", cfg.Cache.Backend)
+	}
+}
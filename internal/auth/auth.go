@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/alias"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+const (
+	// KeyPrefix starts every API key so that leaked keys are easy to spot.
+	KeyPrefix = "gs_"
+
+	keyLength     = 40
+	visiblePrefix = len(KeyPrefix) + 8
+)
+
+// Scopes granted to principals. ScopeAdmin implies every other scope and
+// lets a principal manage users, keys and the links of every user.
+const (
+	ScopeLinksRead   = "links:read"
+	ScopeLinksWrite  = "links:write"
+	ScopeLinksDelete = "links:delete"
+	ScopeAdmin       = "admin"
+)
+
+// DefaultScopes are granted to keys issued without explicit scopes.
+var DefaultScopes = []string{ScopeLinksDelete, ScopeLinksRead, ScopeLinksWrite}
+
+var knownScopes = []string{ScopeLinksRead, ScopeLinksWrite, ScopeLinksDelete, ScopeAdmin}
+
+var ErrUnknownScope = errors.New("unknown scope")
+
+// Principal is the authenticated caller of a request. KeyID is zero when
+// the caller used the configured BasicAuth pair instead of an API key.
+type Principal struct {
+	UserID int64
+	Name   string
+	KeyID  int64
+	Scopes []string
+}
+
+// HasScope reports whether p was granted scope.
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// Allows reports whether p was granted scope or is an admin.
+func (p Principal) Allows(scope string) bool {
+	return p.HasScope(scope) || p.HasScope(ScopeAdmin)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p. Storage accessed with
+// the returned context is limited to the links of p, unless p is an admin.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	ctx = storage.WithCaller(ctx, storage.Caller{UserID: p.UserID, Admin: p.HasScope(ScopeAdmin)})
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal the auth middleware put into ctx.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// HashKey returns the form an API key is stored and looked up in. Keys are
+// long random strings, so a fast unsalted hash is enough.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateKey returns a new random API key.
+func GenerateKey() (string, error) {
+	buf := make([]byte, keyLength)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alias.Base62))))
+		if err != nil {
+			return "", err
+		}
+		buf[i] = alias.Base62[n.Int64()]
+	}
+	return KeyPrefix + string(buf), nil
+}
+
+type KeyIssuer interface {
+	GetUser(ctx context.Context, name string) (storage.User, error)
+	CreateAPIKey(ctx context.Context, key storage.APIKey) (storage.APIKey, error)
+}
+
+// Issue creates an API key for the named user with the given scopes, or
+// DefaultScopes if there are none. The key itself is returned only here;
+// storage keeps just its hash.
+func Issue(ctx context.Context, issuer KeyIssuer, user, name string, scopes []string) (string, storage.APIKey, error) {
+	const op = "auth.Issue"
+
+	scopes = NormalizeScopes(scopes)
+	if err := CheckScopes(scopes); err != nil {
+		return "", storage.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+
+	u, err := issuer.GetUser(ctx, user)
+	if err != nil {
+		return "", storage.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	plain, err := GenerateKey()
+	if err != nil {
+		return "", storage.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := issuer.CreateAPIKey(ctx, storage.APIKey{
+		UserID: u.ID,
+		Name:   name,
+		Prefix: plain[:visiblePrefix],
+		Hash:   HashKey(plain),
+		Scopes: scopes,
+	})
+	if err != nil {
+		return "", storage.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+	key.UserName = u.Name
+
+	return plain, key, nil
+}
+
+// CheckScopes returns ErrUnknownScope naming the first scope that cannot
+// be granted.
+func CheckScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !slices.Contains(knownScopes, scope) {
+			return fmt.Errorf("%w %q", ErrUnknownScope, scope)
+		}
+	}
+	return nil
+}
+
+// FilterScopes normalizes scopes and drops those that are unknown, e.g.
+// the OpenID scopes of a token issued by an identity provider.
+func FilterScopes(scopes []string) []string {
+	return slices.DeleteFunc(NormalizeScopes(scopes), func(scope string) bool {
+		return !slices.Contains(knownScopes, scope)
+	})
+}
+
+// NormalizeScopes splits scope names on whitespace, deduplicates and sorts
+// them.
+func NormalizeScopes(scopes []string) []string {
+	seen := make(map[string]struct{}, len(scopes))
+	out := []string{}
+	for _, field := range scopes {
+		for _, scope := range strings.Fields(field) {
+			if _, ok := seen[scope]; ok {
+				continue
+			}
+			seen[scope] = struct{}{}
+			out = append(out, scope)
+		}
+	}
+	sort.Strings(out)
+
+	return out
+}
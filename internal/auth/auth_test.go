@@ -0,0 +1,74 @@
+package auth_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage/memory"
+)
+
+func TestGenerateKey(t *testing.T) {
+	first, err := auth.GenerateKey()
+	require.NoError(t, err)
+	second, err := auth.GenerateKey()
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(first, auth.KeyPrefix))
+	require.Len(t, first, len(auth.KeyPrefix)+40)
+	require.NotEqual(t, first, second)
+	require.NotEqual(t, auth.HashKey(first), auth.HashKey(second))
+}
+
+func TestIssue(t *testing.T) {
+	ctx := context.Background()
+	keys := memory.New()
+
+	_, _, err := auth.Issue(ctx, keys, "alice", "ci", nil)
+	require.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	_, err = keys.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+
+	plain, key, err := auth.Issue(ctx, keys, "alice", "ci", []string{"links:write links:read", " links:read "})
+	require.NoError(t, err)
+	require.Equal(t, "alice", key.UserName)
+	require.Equal(t, []string{"links:read", "links:write"}, key.Scopes)
+	require.True(t, strings.HasPrefix(plain, key.Prefix))
+
+	found, err := keys.FindAPIKey(ctx, auth.HashKey(plain))
+	require.NoError(t, err)
+	require.Equal(t, key.ID, found.ID)
+	require.NotEqual(t, plain, found.Hash)
+
+	_, key, err = auth.Issue(ctx, keys, "alice", "default", nil)
+	require.NoError(t, err)
+	require.Equal(t, auth.DefaultScopes, key.Scopes)
+
+	_, _, err = auth.Issue(ctx, keys, "alice", "bad", []string{"links:read", "root"})
+	require.ErrorIs(t, err, auth.ErrUnknownScope)
+}
+
+func TestPrincipalAllows(t *testing.T) {
+	reader := auth.Principal{Scopes: []string{auth.ScopeLinksRead}}
+	require.True(t, reader.Allows(auth.ScopeLinksRead))
+	require.False(t, reader.Allows(auth.ScopeLinksWrite))
+	require.False(t, reader.Allows(auth.ScopeAdmin))
+
+	admin := auth.Principal{Scopes: []string{auth.ScopeAdmin}}
+	require.True(t, admin.Allows(auth.ScopeLinksDelete))
+}
+
+func TestPrincipalFrom(t *testing.T) {
+	_, ok := auth.PrincipalFrom(context.Background())
+	require.False(t, ok)
+
+	p := auth.Principal{UserID: 1, Name: "alice", KeyID: 2, Scopes: []string{"links:read"}}
+	got, ok := auth.PrincipalFrom(auth.WithPrincipal(context.Background(), p))
+	require.True(t, ok)
+	require.Equal(t, p, got)
+}
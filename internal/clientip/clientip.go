@@ -0,0 +1,100 @@
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Resolver finds the address of the client that sent a request. Requests
+// from a trusted proxy are attributed to the last untrusted hop of
+// X-Forwarded-For, or to X-Real-IP when there is no X-Forwarded-For; these
+// headers are ignored for any other peer.
+//
+// A nil *Resolver trusts no proxy.
+type Resolver struct {
+	proxies []netip.Prefix
+}
+
+// New returns a Resolver trusting the given addresses and CIDRs.
+func New(trusted []string) (*Resolver, error) {
+	const op = "clientip.New"
+
+	proxies := make([]netip.Prefix, 0, len(trusted))
+	for _, proxy := range trusted {
+		prefix, err := parsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid trusted proxy %q: %w", op, proxy, err)
+		}
+		proxies = append(proxies, prefix)
+	}
+
+	return &Resolver{proxies: proxies}, nil
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// IP returns the address of the client of r.
+func (c *Resolver) IP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+	addr = addr.Unmap()
+	if !c.trusted(addr) {
+		return addr.String()
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				break
+			}
+			addr = hop.Unmap()
+			if !c.trusted(addr) {
+				break
+			}
+		}
+		return addr.String()
+	}
+
+	if realIP, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return realIP.Unmap().String()
+	}
+
+	return addr.String()
+}
+
+func (c *Resolver) trusted(addr netip.Addr) bool {
+	if c == nil {
+		return false
+	}
+
+	for _, prefix := range c.proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package clientip_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/clientip"
+)
+
+func TestResolver_IP(t *testing.T) {
+	ips, err := clientip.New([]string{"10.0.0.0/8", "::1"})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+	}{
+		{
+			name:       "Direct client",
+			remoteAddr: "203.0.113.5:4321",
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "Untrusted forwarding header is ignored",
+			remoteAddr: "203.0.113.5:4321",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.7"},
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "Trusted proxy",
+			remoteAddr: "10.0.0.2:4321",
+			headers:    map[string]string{"X-Forwarded-For": "192.0.2.1, 198.51.100.7, 10.0.0.1"},
+			expected:   "198.51.100.7",
+		},
+		{
+			name:       "Only trusted hops",
+			remoteAddr: "10.0.0.2:4321",
+			headers:    map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.1"},
+			expected:   "10.0.0.3",
+		},
+		{
+			name:       "Trusted proxy with X-Real-IP",
+			remoteAddr: "[::1]:4321",
+			headers:    map[string]string{"X-Real-IP": "2001:db8::7"},
+			expected:   "2001:db8::7",
+		},
+		{
+			name:       "IPv4-mapped peer",
+			remoteAddr: "[::ffff:203.0.113.5]:4321",
+			expected:   "203.0.113.5",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			req.RemoteAddr = tc.remoteAddr
+			for name, value := range tc.headers {
+				req.Header.Set(name, value)
+			}
+
+			require.Equal(t, tc.expected, ips.IP(req))
+		})
+	}
+}
+
+func TestResolver_Nil(t *testing.T) {
+	var ips *clientip.Resolver
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	req.RemoteAddr = "10.0.0.2:4321"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+
+	require.Equal(t, "10.0.0.2", ips.IP(req))
+}
+
+func TestNew_InvalidProxy(t *testing.T) {
+	_, err := clientip.New([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+}
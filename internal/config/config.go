@@ -10,28 +10,167 @@ import (
 )
 
 type Config struct {
-	Env        string `yaml:"env" env-default:"local"`
-	HTTPServer `yaml:"http_server"`
-	Cache      CacheConfig `yaml:"cache_config"`
+	Env         string `yaml:"env" env-default:"local"`
+	HTTPServer  `yaml:"http_server"`
+	Storage     StorageConfig     `yaml:"storage_config"`
+	Cache       CacheConfig       `yaml:"cache_config"`
+	Analytics   AnalyticsConfig   `yaml:"analytics_config"`
+	Trash       TrashConfig       `yaml:"trash_config"`
+	Alias       AliasConfig       `yaml:"alias_config"`
+	URL         URLConfig         `yaml:"url_config"`
+	Destination DestinationConfig `yaml:"destination_config"`
+	Auth        AuthConfig        `yaml:"auth_config"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit_config"`
+	Import      ImportConfig      `yaml:"import_config"`
 }
 
 type HTTPServer struct {
-	Address     string        `yaml:"address" env-default:"localhost" env:"HTTP_SERVER_ADDRESS"`
-	Port        int           `yaml:"port" env-default:"8080" env:"HTTP_SERVER_PORT"`
-	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
-	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"30s"`
-	User        string        `yaml:"user" env-required:"true"`
-	Password    string        `yaml:"password" env-required:"true" env:"HTTP_SERVER_PASSWORD"`
+	Address         string        `yaml:"address" env-default:"localhost" env:"HTTP_SERVER_ADDRESS"`
+	Port            int           `yaml:"port" env-default:"8080" env:"HTTP_SERVER_PORT"`
+	Timeout         time.Duration `yaml:"timeout" env-default:"4s"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env-default:"30s"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"10s"`
+	// TrustedProxies lists the addresses and CIDRs of reverse proxies whose
+	// X-Forwarded-For and X-Real-IP headers name the client.
+	TrustedProxies []string `yaml:"trusted_proxies" env:"HTTP_SERVER_TRUSTED_PROXIES"`
+	// User and Password form an optional BasicAuth pair with admin rights,
+	// accepted next to API keys. It is off while either is empty.
+	User     string `yaml:"user" env:"HTTP_SERVER_USER"`
+	Password string `yaml:"password" env:"HTTP_SERVER_PASSWORD"`
+}
+
+type StorageConfig struct {
+	Backend string `yaml:"backend" env-default:"database" env:"STORAGE_BACKEND"`
 }
 
 type CacheConfig struct {
 	Enabled         bool          `yaml:"enabled"`
+	Backend         string        `yaml:"backend" env-default:"redis" env:"CACHE_BACKEND"`
 	TTL             time.Duration `yaml:"ttl" env-default:"30m"`
 	ReverseIndexTTL time.Duration `yaml:"reverse_index_ttl" env-default:"30m"`
 	PrefixURL       string        `yaml:"prefix_url" env-default:"url:"`
 	PrefixRev       string        `yaml:"prefix_rev" env-default:"rev:"`
 }
 
+type AnalyticsConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	BufferSize    int           `yaml:"buffer_size" env-default:"10000"`
+	BatchSize     int           `yaml:"batch_size" env-default:"500"`
+	FlushInterval time.Duration `yaml:"flush_interval" env-default:"5s"`
+	// IPSalt is hashed with client IPs so that the stored hashes cannot be
+	// reversed by hashing every address; it is required with analytics.
+	IPSalt string `yaml:"ip_salt" env:"ANALYTICS_IP_SALT"`
+}
+
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention" env-default:"720h" env:"TRASH_RETENTION"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+type AliasConfig struct {
+	Generator       string            `yaml:"generator" env-default:"random" env:"ALIAS_GENERATOR"`
+	MinLength       int               `yaml:"min_length" env-default:"6"`
+	Secret          string            `yaml:"secret" env:"ALIAS_SECRET"`
+	CaseInsensitive bool              `yaml:"case_insensitive" env:"ALIAS_CASE_INSENSITIVE"`
+	Policy          AliasPolicyConfig `yaml:"policy"`
+}
+
+type AliasPolicyConfig struct {
+	Charset       string   `yaml:"charset" env-default:"abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"`
+	MinLength     int      `yaml:"min_length" env-default:"3"`
+	MaxLength     int      `yaml:"max_length" env-default:"64"`
+	Reserved      []string `yaml:"reserved" env-default:"api,url,trash,alias,admin,static,health"`
+	BlocklistFile string   `yaml:"blocklist_file" env:"ALIAS_BLOCKLIST_FILE"`
+}
+
+type URLConfig struct {
+	SortQuery      bool     `yaml:"sort_query" env:"URL_SORT_QUERY"`
+	StripTracking  bool     `yaml:"strip_tracking" env:"URL_STRIP_TRACKING"`
+	TrackingParams []string `yaml:"tracking_params" env-default:"utm_source,utm_medium,utm_campaign,utm_term,utm_content,utm_id,gclid,fbclid,msclkid,yclid,mc_cid,mc_eid"`
+}
+
+type DestinationConfig struct {
+	Schemes       []string        `yaml:"schemes" env-default:"http,https"`
+	AllowlistFile string          `yaml:"allowlist_file" env:"DESTINATION_ALLOWLIST_FILE"`
+	DenylistFile  string          `yaml:"denylist_file" env:"DESTINATION_DENYLIST_FILE"`
+	AllowPrivate  bool            `yaml:"allow_private"`
+	ResolveHosts  bool            `yaml:"resolve_hosts"`
+	SelfHosts     []string        `yaml:"self_hosts" env:"DESTINATION_SELF_HOSTS"`
+	Blocklist     BlocklistConfig `yaml:"blocklist"`
+}
+
+type BlocklistConfig struct {
+	DomainsFile     string        `yaml:"domains_file" env:"BLOCKLIST_DOMAINS_FILE"`
+	HashPrefixFile  string        `yaml:"hash_prefix_file" env:"BLOCKLIST_HASH_PREFIX_FILE"`
+	RefreshInterval time.Duration `yaml:"refresh_interval" env-default:"10m"`
+}
+
+type AuthConfig struct {
+	// Mode is AuthKeys to accept API keys and the BasicAuth pair, or
+	// AuthJWT to accept only JWTs signed by a key of the configured JWKS.
+	Mode string    `yaml:"mode" env-default:"keys" env:"AUTH_MODE"`
+	JWT  JWTConfig `yaml:"jwt"`
+}
+
+type JWTConfig struct {
+	// Exactly one of JWKSFile and JWKSURL names the JWKS holding the keys
+	// tokens are signed with.
+	JWKSFile        string        `yaml:"jwks_file" env:"JWT_JWKS_FILE"`
+	JWKSURL         string        `yaml:"jwks_url" env:"JWT_JWKS_URL"`
+	RefreshInterval time.Duration `yaml:"refresh_interval" env-default:"10m"`
+	Issuer          string        `yaml:"issuer" env:"JWT_ISSUER"`
+	Audience        string        `yaml:"audience" env:"JWT_AUDIENCE"`
+	Leeway          time.Duration `yaml:"leeway" env-default:"1m"`
+	// UserClaim names the user a token is issued to, ScopesClaim its
+	// scopes as a space-separated string or an array.
+	UserClaim   string `yaml:"user_claim" env-default:"sub"`
+	ScopesClaim string `yaml:"scopes_claim" env-default:"scope"`
+}
+
+type RateLimitConfig struct {
+	Enabled  bool      `yaml:"enabled"`
+	Backend  string    `yaml:"backend" env-default:"memory" env:"RATE_LIMIT_BACKEND"`
+	Prefix   string    `yaml:"prefix" env-default:"rl:"`
+	Create   RateLimit `yaml:"create"`
+	Delete   RateLimit `yaml:"delete"`
+	Redirect RateLimit `yaml:"redirect"`
+	// Bulk limits batch saves and imports, which may create many links
+	// with a single request. They are counted against Create as well.
+	Bulk RateLimit `yaml:"bulk"`
+}
+
+// ImportConfig bounds imports over HTTP. Imports are parsed whole before
+// anything is written, so both the body and its record count are capped.
+type ImportConfig struct {
+	MaxBytes   int64 `yaml:"max_bytes" env-default:"33554432" env:"IMPORT_MAX_BYTES"`
+	MaxRecords int   `yaml:"max_records" env-default:"100000" env:"IMPORT_MAX_RECORDS"`
+}
+
+// RateLimit lets Rate requests per Period through, in bursts of at most
+// Burst requests; Burst defaults to Rate. A zero Rate disables the limit.
+type RateLimit struct {
+	Rate   int           `yaml:"rate"`
+	Period time.Duration `yaml:"period" env-default:"1m"`
+	Burst  int           `yaml:"burst"`
+}
+
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+
+	CacheRedis  = "redis"
+	CacheMemory = "memory"
+
+	AliasRandom   = "random"
+	AliasSequence = "sequence"
+
+	AuthKeys = "keys"
+	AuthJWT  = "jwt"
+
+	RateLimitMemory = "memory"
+	RateLimitRedis  = "redis"
+)
+
 func MustLoad() (*Config, string, string) {
 	if err := godotenv.Load(".env"); err != nil {
 		log.Println("No .env file found, using system environment")
@@ -54,12 +193,18 @@ func MustLoad() (*Config, string, string) {
 	}
 
 	db_str := os.Getenv("DATABASE_URL")
-	if db_str == "" {
+	if db_str == "" && cfg.Storage.Backend != StorageMemory {
 		log.Fatal("DATABASE_URL is not set")
 	}
 
+	if cfg.Analytics.Enabled && cfg.Analytics.IPSalt == "" {
+		log.Fatal("ANALYTICS_IP_SALT is not set")
+	}
+
 	cache_str := os.Getenv("CACHE_URL")
-	if cache_str == "" {
+	usesRedis := cfg.Cache.Enabled && cfg.Cache.Backend == CacheRedis ||
+		cfg.RateLimit.Enabled && cfg.RateLimit.Backend == RateLimitRedis
+	if cache_str == "" && usesRedis {
 		log.Println("Warning: CACHE_URL is not set")
 	}
 
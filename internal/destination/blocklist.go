@@ -0,0 +1,255 @@
+package destination
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+	"github.com/n0f4ph4mst3r/goshort/internal/urlnorm"
+)
+
+var (
+	ErrListedDomain = errors.New("destination domain is on the blocklist")
+	ErrListedURL    = errors.New("destination URL is on the blocklist")
+)
+
+const (
+	minPrefixLen = 4
+	maxPrefixLen = sha256.Size
+
+	maxHostSuffixes = 5
+	maxPathPrefixes = 4
+)
+
+// Blocklist matches destinations against a list of bad domains and a
+// Safe-Browsing-style database of SHA-256 hash prefixes. Both are read
+// from local files and re-read on every refresh interval until closed;
+// if a refresh fails, the lists loaded before stay in use.
+//
+// A URL matches the hash database when the hash of any of its host suffix
+// and path prefix expressions starts with a listed prefix. There is no
+// full-hash confirmation, so a prefix match counts as a hit.
+//
+// A nil *Blocklist is valid and matches nothing.
+type Blocklist struct {
+	log *slog.Logger
+	cfg *config.BlocklistConfig
+
+	mu       sync.RWMutex
+	domains  map[string]struct{}
+	prefixes map[string]struct{}
+	lengths  []int
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewBlocklist loads the lists named in cfg and starts refreshing them.
+func NewBlocklist(log *slog.Logger, cfg *config.BlocklistConfig) (*Blocklist, error) {
+	const op = "destination.NewBlocklist"
+
+	b := &Blocklist{
+		log:  log.With(slog.String("component", "destination/blocklist")),
+		cfg:  cfg,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	if err := b.reload(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	go b.run()
+
+	return b, nil
+}
+
+// Check returns ErrListedDomain or ErrListedURL if raw is on the blocklist.
+func (b *Blocklist) Check(_ context.Context, raw string) error {
+	if b == nil {
+		return nil
+	}
+
+	canonical, err := (&urlnorm.Canonicalizer{}).Canonicalize(raw)
+	if err != nil {
+		return nil
+	}
+	u, err := url.Parse(canonical)
+	if err != nil {
+		return nil
+	}
+	host := normalizeHost(u.Hostname())
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if matchDomain(b.domains, host) {
+		return ErrListedDomain
+	}
+
+	if len(b.prefixes) == 0 {
+		return nil
+	}
+	for _, expr := range expressions(host, u.EscapedPath(), u.RawQuery) {
+		sum := sha256.Sum256([]byte(expr))
+		for _, n := range b.lengths {
+			if _, ok := b.prefixes[string(sum[:n])]; ok {
+				return ErrListedURL
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close stops refreshing, or gives up when ctx is done.
+func (b *Blocklist) Close(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	b.once.Do(func() { close(b.stop) })
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Blocklist) run() {
+	defer close(b.done)
+
+	if b.cfg.RefreshInterval <= 0 {
+		<-b.stop
+		return
+	}
+
+	ticker := time.NewTicker(b.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := b.reload(); err != nil {
+				b.log.Error("failed to refresh blocklist", sl.Err(err))
+			}
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+func (b *Blocklist) reload() error {
+	domains, err := readDomains(b.cfg.DomainsFile)
+	if err != nil {
+		return err
+	}
+	prefixes, lengths, err := readPrefixes(b.cfg.HashPrefixFile)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.domains, b.prefixes, b.lengths = domains, prefixes, lengths
+	b.mu.Unlock()
+
+	b.log.Info("blocklist loaded", slog.Int("domains", len(domains)), slog.Int("prefixes", len(prefixes)))
+
+	return nil
+}
+
+// readPrefixes reads one hex-encoded hash prefix per line, skipping blank
+// lines and # comments. It also returns the distinct prefix lengths, so
+// that a lookup tries only the lengths present in the file.
+func readPrefixes(path string) (map[string]struct{}, []int, error) {
+	prefixes := make(map[string]struct{})
+	if path == "" {
+		return prefixes, nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var lengths []int
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		prefix, err := hex.DecodeString(text)
+		if err != nil || len(prefix) < minPrefixLen || len(prefix) > maxPrefixLen {
+			return nil, nil, fmt.Errorf("%s:%d: invalid hash prefix", path, line)
+		}
+
+		prefixes[string(prefix)] = struct{}{}
+		if !slices.Contains(lengths, len(prefix)) {
+			lengths = append(lengths, len(prefix))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return prefixes, lengths, nil
+}
+
+// expressions returns the host suffix / path prefix combinations of a
+// canonical URL that are hashed for a lookup: the exact host and up to four
+// suffixes built from its last five labels, combined with the exact path
+// with and without the query and up to four path prefixes starting at "/".
+func expressions(host, path, query string) []string {
+	hosts := []string{host}
+	if parseIP(host) == nil {
+		labels := strings.Split(host, ".")
+		for i := max(len(labels)-5, 1); i < len(labels)-1 && len(hosts) < maxHostSuffixes; i++ {
+			hosts = append(hosts, strings.Join(labels[i:], "."))
+		}
+	}
+
+	var paths []string
+	if query != "" {
+		paths = append(paths, path+"?"+query)
+	}
+	paths = append(paths, path)
+
+	prefix := "/"
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i < maxPathPrefixes; i++ {
+		if !slices.Contains(paths, prefix) {
+			paths = append(paths, prefix)
+		}
+		if i >= len(segments)-1 {
+			break
+		}
+		prefix += segments[i] + "/"
+	}
+
+	exprs := make([]string, 0, len(hosts)*len(paths))
+	for _, h := range hosts {
+		for _, p := range paths {
+			exprs = append(exprs, h+p)
+		}
+	}
+
+	return exprs
+}
@@ -0,0 +1,86 @@
+package destination_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/destination"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+)
+
+func prefix(expr string, n int) string {
+	sum := sha256.Sum256([]byte(expr))
+	return hex.EncodeToString(sum[:n])
+}
+
+func TestBlocklist_Check(t *testing.T) {
+	blocklist, err := destination.NewBlocklist(sldiscard.NewDiscardLogger(), &config.BlocklistConfig{
+		DomainsFile: writeList(t, "malware.example\n"),
+		HashPrefixFile: writeList(t, "# prefixes of various lengths\n"+
+			prefix("evil.example/1/", 4)+"\n"+
+			prefix("host.example/a.html?x=1", 8)+"\n"+
+			prefix("b.c.d.e.f/", 32)+"\n"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, blocklist.Close(context.Background())) })
+
+	cases := []struct {
+		url string
+		err error
+	}{
+		{"https://malware.example/", destination.ErrListedDomain},
+		{"https://cdn.Malware.example/x.js", destination.ErrListedDomain},
+		{"https://evil.example/1/2.html?param=1", destination.ErrListedURL},
+		{"https://www.evil.example/1/", destination.ErrListedURL},
+		{"HTTP://EVIL.example:80/a/../1/", destination.ErrListedURL},
+		{"https://evil.example/2/", nil},
+		{"https://host.example/a.html?x=1", destination.ErrListedURL},
+		{"https://host.example/a.html?x=2", nil},
+		{"https://a.b.c.d.e.f/any/path", destination.ErrListedURL},
+		{"https://b.c.d.e.f.example/", nil},
+		{"https://safe.example/", nil},
+		{"not a url", nil},
+	}
+
+	for _, tc := range cases {
+		require.ErrorIs(t, blocklist.Check(context.Background(), tc.url), tc.err, tc.url)
+		if tc.err == nil {
+			require.NoError(t, blocklist.Check(context.Background(), tc.url), tc.url)
+		}
+	}
+}
+
+func TestBlocklist_InvalidPrefix(t *testing.T) {
+	for _, lines := range []string{"zz\n", "abcdef\n", prefix("x", 32) + "00\n"} {
+		_, err := destination.NewBlocklist(sldiscard.NewDiscardLogger(), &config.BlocklistConfig{
+			HashPrefixFile: writeList(t, lines),
+		})
+		require.Error(t, err, lines)
+	}
+}
+
+func TestBlocklist_Nil(t *testing.T) {
+	var blocklist *destination.Blocklist
+	require.NoError(t, blocklist.Check(context.Background(), "https://malware.example/"))
+	require.NoError(t, blocklist.Close(context.Background()))
+}
+
+func TestChain(t *testing.T) {
+	policy, err := destination.NewPolicy(&config.DestinationConfig{Schemes: []string{"https"}}, nil)
+	require.NoError(t, err)
+	blocklist, err := destination.NewBlocklist(sldiscard.NewDiscardLogger(), &config.BlocklistConfig{
+		DomainsFile: writeList(t, "malware.example\n"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, blocklist.Close(context.Background())) })
+
+	chain := destination.Chain{policy, blocklist}
+	require.NoError(t, chain.Check(context.Background(), "https://safe.example/"))
+	require.ErrorIs(t, chain.Check(context.Background(), "http://malware.example/"), destination.ErrScheme)
+	require.ErrorIs(t, chain.Check(context.Background(), "https://malware.example/"), destination.ErrListedDomain)
+}
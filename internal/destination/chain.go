@@ -0,0 +1,19 @@
+package destination
+
+import "context"
+
+type Checker interface {
+	Check(ctx context.Context, raw string) error
+}
+
+// Chain runs several checks in order and returns the first rejection.
+type Chain []Checker
+
+func (c Chain) Check(ctx context.Context, raw string) error {
+	for _, checker := range c {
+		if err := checker.Check(ctx, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package destination
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+)
+
+var (
+	ErrScheme        = errors.New("destination scheme is not allowed")
+	ErrNoHost        = errors.New("destination has no host")
+	ErrDenied        = errors.New("destination domain is blocked")
+	ErrNotAllowed    = errors.New("destination domain is not allowed")
+	ErrPrivate       = errors.New("destination points to a private or loopback address")
+	ErrSelfReference = errors.New("destination points back to this shortener")
+	ErrUnresolvable  = errors.New("destination host cannot be resolved")
+)
+
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// Policy decides whether a URL may be used as a link destination.
+//
+// Domain lists match the listed domain and all of its subdomains. When an
+// allowlist is configured, only hosts on it are accepted. Hosts given as IP
+// literals, including the legacy numeric IPv4 spellings browsers accept,
+// are rejected when they are not publicly routable; with a resolver, host
+// names are resolved and checked the same way.
+type Policy struct {
+	schemes      map[string]struct{}
+	allow        map[string]struct{}
+	deny         map[string]struct{}
+	self         map[string]struct{}
+	allowPrivate bool
+	resolver     Resolver
+}
+
+// NewPolicy loads the domain lists named in cfg. The resolver is used only
+// when cfg.ResolveHosts is set.
+func NewPolicy(cfg *config.DestinationConfig, resolver Resolver) (*Policy, error) {
+	const op = "destination.NewPolicy"
+
+	p := &Policy{
+		schemes:      make(map[string]struct{}),
+		self:         make(map[string]struct{}),
+		allowPrivate: cfg.AllowPrivate,
+	}
+	if cfg.ResolveHosts {
+		p.resolver = resolver
+	}
+
+	for _, scheme := range cfg.Schemes {
+		if scheme = strings.TrimSpace(scheme); scheme != "" {
+			p.schemes[strings.ToLower(scheme)] = struct{}{}
+		}
+	}
+	for _, host := range cfg.SelfHosts {
+		if host = normalizeHost(host); host != "" {
+			p.self[host] = struct{}{}
+		}
+	}
+
+	var err error
+	if p.allow, err = readDomains(cfg.AllowlistFile); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if p.deny, err = readDomains(cfg.DenylistFile); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return p, nil
+}
+
+// Check returns nil if raw is an acceptable destination, or the first rule
+// it breaks. A nil *Policy accepts every destination.
+func (p *Policy) Check(ctx context.Context, raw string) error {
+	if p == nil {
+		return nil
+	}
+
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ErrNoHost
+	}
+
+	if _, ok := p.schemes[strings.ToLower(u.Scheme)]; len(p.schemes) > 0 && !ok {
+		return ErrScheme
+	}
+
+	host := normalizeHost(u.Hostname())
+	if host == "" {
+		return ErrNoHost
+	}
+
+	if _, ok := p.self[host]; ok {
+		return ErrSelfReference
+	}
+	if matchDomain(p.deny, host) {
+		return ErrDenied
+	}
+	if len(p.allow) > 0 && !matchDomain(p.allow, host) {
+		return ErrNotAllowed
+	}
+
+	if p.allowPrivate {
+		return nil
+	}
+
+	if ip := parseIP(host); ip != nil {
+		if !public(ip) {
+			return ErrPrivate
+		}
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivate
+	}
+
+	if p.resolver != nil {
+		addrs, err := p.resolver.LookupIPAddr(ctx, host)
+		if err != nil || len(addrs) == 0 {
+			return ErrUnresolvable
+		}
+		for _, addr := range addrs {
+			if !public(addr.IP) {
+				return ErrPrivate
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalizeHost lower-cases a host, drops a trailing dot and converts an
+// internationalized name to punycode, so that it compares equal to the
+// entries of the domain lists.
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if host == "" || net.ParseIP(host) != nil {
+		return host
+	}
+	if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+		return ascii
+	}
+	return host
+}
+
+func matchDomain(domains map[string]struct{}, host string) bool {
+	for {
+		if _, ok := domains[host]; ok {
+			return true
+		}
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			return false
+		}
+		host = parent
+	}
+}
+
+func public(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast()
+}
+
+// parseIP parses an IP literal, including the shortened, octal and
+// hexadecimal IPv4 forms (127.1, 0x7f.0.0.1, 2130706433) that browsers
+// still resolve.
+func parseIP(host string) net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip
+	}
+
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return nil
+	}
+
+	nums := make([]uint64, len(parts))
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 0, 32)
+		if err != nil {
+			return nil
+		}
+		nums[i] = n
+	}
+
+	// All but the last part are single bytes; the last part fills the
+	// remaining bytes of the address.
+	var v uint64
+	for i, n := range nums[:len(nums)-1] {
+		if n > 0xff {
+			return nil
+		}
+		v |= n << (24 - 8*i)
+	}
+	last := nums[len(nums)-1]
+	if last >= 1<<(8*(5-len(nums))) {
+		return nil
+	}
+	v |= last
+
+	return net.IPv4(byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// readDomains reads one domain per line, skipping blank lines and
+// # comments. An empty path yields an empty list.
+func readDomains(path string) (map[string]struct{}, error) {
+	domains := make(map[string]struct{})
+	if path == "" {
+		return domains, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains[normalizeHost(strings.TrimPrefix(line, "*."))] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return domains, nil
+}
@@ -0,0 +1,139 @@
+package destination_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/destination"
+)
+
+type resolver map[string][]string
+
+func (r resolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	addrs := make([]net.IPAddr, len(ips))
+	for i, ip := range ips {
+		addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+	}
+	return addrs, nil
+}
+
+func writeList(t *testing.T, lines string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "list.txt")
+	require.NoError(t, os.WriteFile(path, []byte(lines), 0o600))
+	return path
+}
+
+func TestPolicy_Check(t *testing.T) {
+	policy, err := destination.NewPolicy(&config.DestinationConfig{
+		Schemes:      []string{"http", "https"},
+		DenylistFile: writeList(t, "# phishing\nevil.example\n*.bad.example\nbücher.example\n"),
+		SelfHosts:    []string{"Sho.rt"},
+	}, nil)
+	require.NoError(t, err)
+
+	cases := []struct {
+		url string
+		err error
+	}{
+		{"https://duckduckgo.com/?q=go", nil},
+		{"HTTP://Example.com/", nil},
+		{"javascript:alert(1)", destination.ErrScheme},
+		{"file:///etc/passwd", destination.ErrScheme},
+		{"ftp://example.com/file", destination.ErrScheme},
+		{"http:///path", destination.ErrNoHost},
+		{"https://evil.example/login", destination.ErrDenied},
+		{"https://WWW.Evil.Example./login", destination.ErrDenied},
+		{"https://cdn.bad.example/", destination.ErrDenied},
+		{"https://bad.example/", destination.ErrDenied},
+		{"https://notevil.example/", nil},
+		{"https://xn--bcher-kva.example/", destination.ErrDenied},
+		{"http://127.0.0.1/admin", destination.ErrPrivate},
+		{"http://127.1/admin", destination.ErrPrivate},
+		{"http://2130706433/", destination.ErrPrivate},
+		{"http://0x7f.0.0.1/", destination.ErrPrivate},
+		{"http://0.0.0.0:8080/", destination.ErrPrivate},
+		{"http://10.1.2.3/", destination.ErrPrivate},
+		{"http://192.168.0.1/", destination.ErrPrivate},
+		{"http://169.254.169.254/latest/meta-data/", destination.ErrPrivate},
+		{"http://[::1]/", destination.ErrPrivate},
+		{"http://[fe80::1]/", destination.ErrPrivate},
+		{"http://[::ffff:127.0.0.1]/", destination.ErrPrivate},
+		{"http://localhost:8080/", destination.ErrPrivate},
+		{"http://api.localhost/", destination.ErrPrivate},
+		{"http://8.8.8.8/", nil},
+		{"https://sho.rt/abc", destination.ErrSelfReference},
+		{"https://SHO.RT:443/abc", destination.ErrSelfReference},
+	}
+
+	for _, tc := range cases {
+		require.ErrorIs(t, policy.Check(context.Background(), tc.url), tc.err, tc.url)
+		if tc.err == nil {
+			require.NoError(t, policy.Check(context.Background(), tc.url), tc.url)
+		}
+	}
+}
+
+func TestPolicy_Allowlist(t *testing.T) {
+	policy, err := destination.NewPolicy(&config.DestinationConfig{
+		AllowlistFile: writeList(t, "example.com\n"),
+		DenylistFile:  writeList(t, "private.example.com\n"),
+	}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, policy.Check(context.Background(), "https://example.com/"))
+	require.NoError(t, policy.Check(context.Background(), "https://docs.example.com/"))
+	require.ErrorIs(t, policy.Check(context.Background(), "https://private.example.com/"), destination.ErrDenied)
+	require.ErrorIs(t, policy.Check(context.Background(), "https://example.org/"), destination.ErrNotAllowed)
+}
+
+func TestPolicy_ResolveHosts(t *testing.T) {
+	r := resolver{
+		"public.example":   {"93.184.216.34"},
+		"internal.example": {"93.184.216.34", "10.0.0.5"},
+	}
+
+	policy, err := destination.NewPolicy(&config.DestinationConfig{ResolveHosts: true}, r)
+	require.NoError(t, err)
+
+	require.NoError(t, policy.Check(context.Background(), "https://public.example/"))
+	require.ErrorIs(t, policy.Check(context.Background(), "https://internal.example/"), destination.ErrPrivate)
+	require.ErrorIs(t, policy.Check(context.Background(), "https://missing.example/"), destination.ErrUnresolvable)
+
+	unresolved, err := destination.NewPolicy(&config.DestinationConfig{}, r)
+	require.NoError(t, err)
+	require.NoError(t, unresolved.Check(context.Background(), "https://internal.example/"))
+}
+
+func TestPolicy_AllowPrivate(t *testing.T) {
+	policy, err := destination.NewPolicy(&config.DestinationConfig{AllowPrivate: true}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, policy.Check(context.Background(), "http://127.0.0.1/admin"))
+	require.NoError(t, policy.Check(context.Background(), "http://localhost:8080/"))
+}
+
+func TestPolicy_Nil(t *testing.T) {
+	var policy *destination.Policy
+	require.NoError(t, policy.Check(context.Background(), "javascript:alert(1)"))
+}
+
+func TestPolicy_MissingList(t *testing.T) {
+	_, err := destination.NewPolicy(&config.DestinationConfig{
+		DenylistFile: filepath.Join(t.TempDir(), "missing.txt"),
+	}, nil)
+	require.Error(t, err)
+}
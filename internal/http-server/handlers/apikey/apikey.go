@@ -0,0 +1,279 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+type UserRequest struct {
+	Name string `json:"name" validate:"required,max=64"`
+}
+
+type UserResponse struct {
+	response.Message
+	User storage.User `json:"user"`
+}
+
+type UsersResponse struct {
+	response.Message
+	Users []storage.User `json:"users"`
+}
+
+type KeyRequest struct {
+	Name   string   `json:"name,omitempty" validate:"max=64"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// KeyResponse carries a newly issued key. Key is shown only once.
+type KeyResponse struct {
+	response.Message
+	Key    string         `json:"key"`
+	APIKey storage.APIKey `json:"api_key"`
+}
+
+type KeysResponse struct {
+	response.Message
+	Keys []storage.APIKey `json:"keys"`
+}
+
+type UserCreator interface {
+	CreateUser(ctx context.Context, name string) (storage.User, error)
+}
+
+type UserLister interface {
+	ListUsers(ctx context.Context) ([]storage.User, error)
+}
+
+type KeyIssuer interface {
+	GetUser(ctx context.Context, name string) (storage.User, error)
+	CreateAPIKey(ctx context.Context, key storage.APIKey) (storage.APIKey, error)
+}
+
+type KeyLister interface {
+	GetUser(ctx context.Context, name string) (storage.User, error)
+	ListAPIKeys(ctx context.Context, userID int64) ([]storage.APIKey, error)
+}
+
+type KeyRevoker interface {
+	RevokeAPIKey(ctx context.Context, id int64) error
+}
+
+func NewCreateUser(log *slog.Logger, creator UserCreator) http.HandlerFunc {
+	validate := validator.New()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.apikey.NewCreateUser")
+
+		var req UserRequest
+		if !decode(log, w, r, validate, &req) {
+			return
+		}
+
+		user, err := creator.CreateUser(r.Context(), req.Name)
+		if errors.Is(err, storage.ErrUserExists) {
+			sl.WriteResponse(log, w, r, http.StatusConflict,
+				response.Error("user already exists"),
+				"failed to create user", slog.String("name", req.Name))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to create user", sl.Err(err))
+
+			return
+		}
+
+		sl.WriteResponse(log, w, r, 0, UserResponse{
+			Message: response.OK(),
+			User:    user,
+		}, "user created", slog.String("name", user.Name))
+	}
+}
+
+func NewListUsers(log *slog.Logger, lister UserLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.apikey.NewListUsers")
+
+		users, err := lister.ListUsers(r.Context())
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to list users", sl.Err(err))
+
+			return
+		}
+
+		sl.WriteResponse(log, w, r, 0, UsersResponse{
+			Message: response.OK(),
+			Users:   users,
+		}, "users listed", slog.Int("count", len(users)))
+	}
+}
+
+// NewIssue creates an API key for the user named in the path.
+func NewIssue(log *slog.Logger, issuer KeyIssuer) http.HandlerFunc {
+	validate := validator.New()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.apikey.NewIssue")
+
+		user := chi.URLParam(r, "user")
+
+		var req KeyRequest
+		if !decode(log, w, r, validate, &req) {
+			return
+		}
+
+		if err := auth.CheckScopes(auth.NormalizeScopes(req.Scopes)); err != nil {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error(err.Error()),
+				"invalid scopes", sl.Err(err))
+
+			return
+		}
+
+		plain, key, err := auth.Issue(r.Context(), issuer, user, req.Name, req.Scopes)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			sl.WriteResponse(log, w, r, http.StatusNotFound,
+				response.Error("user not found"),
+				"failed to issue API key", slog.String("user", user))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to issue API key", sl.Err(err))
+
+			return
+		}
+
+		sl.WriteResponse(log, w, r, 0, KeyResponse{
+			Message: response.OK(),
+			Key:     plain,
+			APIKey:  key,
+		}, "API key issued", slog.String("user", user), slog.Int64("key_id", key.ID))
+	}
+}
+
+func NewListKeys(log *slog.Logger, lister KeyLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.apikey.NewListKeys")
+
+		name := chi.URLParam(r, "user")
+
+		user, err := lister.GetUser(r.Context(), name)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			sl.WriteResponse(log, w, r, http.StatusNotFound,
+				response.Error("user not found"),
+				"failed to list API keys", slog.String("user", name))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to get user", sl.Err(err))
+
+			return
+		}
+
+		keys, err := lister.ListAPIKeys(r.Context(), user.ID)
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to list API keys", sl.Err(err))
+
+			return
+		}
+
+		sl.WriteResponse(log, w, r, 0, KeysResponse{
+			Message: response.OK(),
+			Keys:    keys,
+		}, "API keys listed", slog.String("user", name), slog.Int("count", len(keys)))
+	}
+}
+
+func NewRevoke(log *slog.Logger, revoker KeyRevoker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.apikey.NewRevoke")
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || id <= 0 {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("invalid key id"),
+				"invalid key id", slog.String("id", chi.URLParam(r, "id")))
+
+			return
+		}
+
+		err = revoker.RevokeAPIKey(r.Context(), id)
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			sl.WriteResponse(log, w, r, http.StatusNotFound,
+				response.Error("API key not found"),
+				"failed to revoke API key", slog.Int64("key_id", id))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to revoke API key", sl.Err(err))
+
+			return
+		}
+
+		sl.WriteResponse(log, w, r, 0, response.OK(), "API key revoked", slog.Int64("key_id", id))
+	}
+}
+
+func decode(log *slog.Logger, w http.ResponseWriter, r *http.Request, validate *validator.Validate, req any) bool {
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		sl.WriteResponse(log, w, r, http.StatusBadRequest,
+			response.Error("empty request"),
+			"request body is empty")
+
+		return false
+	}
+	if err != nil {
+		sl.WriteResponse(log, w, r, http.StatusBadRequest,
+			response.Error("invalid request body"),
+			"failed to decode request body", sl.Err(err))
+
+		return false
+	}
+
+	if err := validate.Struct(req); err != nil {
+		var validateErr validator.ValidationErrors
+		if errors.As(err, &validateErr) {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.ValidationError(validateErr),
+				"request validation failed", sl.Err(err))
+
+			return false
+		}
+		sl.WriteResponse(log, w, r, http.StatusBadRequest,
+			response.Error("invalid request"),
+			"request validation failed", sl.Err(err))
+
+		return false
+	}
+
+	return true
+}
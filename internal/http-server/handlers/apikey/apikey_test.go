@@ -0,0 +1,178 @@
+package apikey_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/apikey"
+	mocks "github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/apikey/mocks"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+func TestCreateUserHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		body         string
+		mockError    error
+		callsStorage bool
+		expectedCode int
+		respError    string
+	}{
+		{name: "Success", body: `{"name": "alice"}`, callsStorage: true, expectedCode: http.StatusOK},
+		{name: "Exists", body: `{"name": "alice"}`, callsStorage: true, mockError: storage.ErrUserExists, expectedCode: http.StatusConflict, respError: "user already exists"},
+		{name: "Missing name", body: `{}`, expectedCode: http.StatusBadRequest, respError: "field Name is a required field"},
+		{name: "Empty body", body: ``, expectedCode: http.StatusBadRequest, respError: "empty request"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userCreatorMock := mocks.NewMockUserCreator(t)
+			if tc.callsStorage {
+				userCreatorMock.On("CreateUser", mock.Anything, "alice").
+					Return(storage.User{ID: 1, Name: "alice"}, tc.mockError).Once()
+			}
+
+			handler := apikey.NewCreateUser(sldiscard.NewDiscardLogger(), userCreatorMock)
+
+			req, err := http.NewRequest(http.MethodPost, "/api/users", strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			var resp apikey.UserResponse
+			require.Equal(t, tc.expectedCode, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+			if tc.respError == "" {
+				require.Equal(t, "alice", resp.User.Name)
+			}
+		})
+	}
+}
+
+func TestIssueHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		user         string
+		scopes       string
+		userError    error
+		createError  error
+		expectedCode int
+		respError    string
+	}{
+		{name: "Success", user: "alice", expectedCode: http.StatusOK},
+		{name: "Unknown scope", user: "alice", scopes: `["links:read", "sudo"]`, expectedCode: http.StatusBadRequest, respError: `unknown scope "sudo"`},
+		{name: "Unknown user", user: "bob", userError: storage.ErrUserNotFound, expectedCode: http.StatusNotFound, respError: "user not found"},
+		{name: "Storage failure", user: "alice", createError: errors.New("connection reset"), expectedCode: http.StatusInternalServerError, respError: "internal error"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyIssuerMock := mocks.NewMockKeyIssuer(t)
+			if tc.expectedCode != http.StatusBadRequest {
+				keyIssuerMock.On("GetUser", mock.Anything, tc.user).
+					Return(storage.User{ID: 4, Name: tc.user}, tc.userError).Once()
+			}
+			if tc.userError == nil && tc.expectedCode != http.StatusBadRequest {
+				keyIssuerMock.On("CreateAPIKey", mock.Anything, mock.MatchedBy(func(key storage.APIKey) bool {
+					return key.UserID == 4 && key.Name == "ci" && strings.HasPrefix(key.Prefix, auth.KeyPrefix) &&
+						len(key.Hash) == 64 && len(key.Scopes) == 2 && key.Scopes[0] == "links:read"
+				})).Return(func(_ context.Context, key storage.APIKey) storage.APIKey {
+					key.ID = 9
+					return key
+				}, tc.createError).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Post("/api/users/{user}/keys", apikey.NewIssue(sldiscard.NewDiscardLogger(), keyIssuerMock))
+
+			scopes := tc.scopes
+			if scopes == "" {
+				scopes = `["links:write", "links:read", "links:read"]`
+			}
+			body := `{"name": "ci", "scopes": ` + scopes + `}`
+			req, err := http.NewRequest(http.MethodPost, "/api/users/"+tc.user+"/keys", strings.NewReader(body))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			var resp apikey.KeyResponse
+			require.Equal(t, tc.expectedCode, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+			if tc.respError != "" {
+				return
+			}
+
+			require.True(t, strings.HasPrefix(resp.Key, resp.APIKey.Prefix))
+			require.Equal(t, int64(9), resp.APIKey.ID)
+			require.Equal(t, "alice", resp.APIKey.UserName)
+			require.Equal(t, []string{"links:read", "links:write"}, resp.APIKey.Scopes)
+			require.NotContains(t, rr.Body.String(), auth.HashKey(resp.Key))
+		})
+	}
+}
+
+func TestRevokeHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		id           string
+		mockError    error
+		callsStorage bool
+		expectedCode int
+		respError    string
+	}{
+		{name: "Success", id: "3", callsStorage: true, expectedCode: http.StatusOK},
+		{name: "Not found", id: "3", callsStorage: true, mockError: storage.ErrKeyNotFound, expectedCode: http.StatusNotFound, respError: "API key not found"},
+		{name: "Invalid id", id: "abc", expectedCode: http.StatusBadRequest, respError: "invalid key id"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyRevokerMock := mocks.NewMockKeyRevoker(t)
+			if tc.callsStorage {
+				keyRevokerMock.On("RevokeAPIKey", mock.Anything, int64(3)).Return(tc.mockError).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Delete("/api/keys/{id}", apikey.NewRevoke(sldiscard.NewDiscardLogger(), keyRevokerMock))
+
+			req, err := http.NewRequest(http.MethodDelete, "/api/keys/"+tc.id, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			var resp response.Message
+			require.Equal(t, tc.expectedCode, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package apikey_mocks
+
+import (
+	"context"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockKeyIssuer creates a new instance of MockKeyIssuer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockKeyIssuer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockKeyIssuer {
+	mock := &MockKeyIssuer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockKeyIssuer is an autogenerated mock type for the KeyIssuer type
+type MockKeyIssuer struct {
+	mock.Mock
+}
+
+type MockKeyIssuer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockKeyIssuer) EXPECT() *MockKeyIssuer_Expecter {
+	return &MockKeyIssuer_Expecter{mock: &_m.Mock}
+}
+
+// CreateAPIKey provides a mock function for the type MockKeyIssuer
+func (_mock *MockKeyIssuer) CreateAPIKey(ctx context.Context, key storage.APIKey) (storage.APIKey, error) {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIKey")
+	}
+
+	var r0 storage.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, storage.APIKey) (storage.APIKey, error)); ok {
+		return returnFunc(ctx, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, storage.APIKey) storage.APIKey); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Get(0).(storage.APIKey)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, storage.APIKey) error); ok {
+		r1 = returnFunc(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKeyIssuer_CreateAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAPIKey'
+type MockKeyIssuer_CreateAPIKey_Call struct {
+	*mock.Call
+}
+
+// CreateAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key storage.APIKey
+func (_e *MockKeyIssuer_Expecter) CreateAPIKey(ctx interface{}, key interface{}) *MockKeyIssuer_CreateAPIKey_Call {
+	return &MockKeyIssuer_CreateAPIKey_Call{Call: _e.mock.On("CreateAPIKey", ctx, key)}
+}
+
+func (_c *MockKeyIssuer_CreateAPIKey_Call) Run(run func(ctx context.Context, key storage.APIKey)) *MockKeyIssuer_CreateAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 storage.APIKey
+		if args[1] != nil {
+			arg1 = args[1].(storage.APIKey)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKeyIssuer_CreateAPIKey_Call) Return(aPIKey storage.APIKey, err error) *MockKeyIssuer_CreateAPIKey_Call {
+	_c.Call.Return(aPIKey, err)
+	return _c
+}
+
+func (_c *MockKeyIssuer_CreateAPIKey_Call) RunAndReturn(run func(ctx context.Context, key storage.APIKey) (storage.APIKey, error)) *MockKeyIssuer_CreateAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUser provides a mock function for the type MockKeyIssuer
+func (_mock *MockKeyIssuer) GetUser(ctx context.Context, name string) (storage.User, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 storage.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (storage.User, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) storage.User); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		r0 = ret.Get(0).(storage.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKeyIssuer_GetUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUser'
+type MockKeyIssuer_GetUser_Call struct {
+	*mock.Call
+}
+
+// GetUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockKeyIssuer_Expecter) GetUser(ctx interface{}, name interface{}) *MockKeyIssuer_GetUser_Call {
+	return &MockKeyIssuer_GetUser_Call{Call: _e.mock.On("GetUser", ctx, name)}
+}
+
+func (_c *MockKeyIssuer_GetUser_Call) Run(run func(ctx context.Context, name string)) *MockKeyIssuer_GetUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKeyIssuer_GetUser_Call) Return(user storage.User, err error) *MockKeyIssuer_GetUser_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockKeyIssuer_GetUser_Call) RunAndReturn(run func(ctx context.Context, name string) (storage.User, error)) *MockKeyIssuer_GetUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,173 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package apikey_mocks
+
+import (
+	"context"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockKeyLister creates a new instance of MockKeyLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockKeyLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockKeyLister {
+	mock := &MockKeyLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockKeyLister is an autogenerated mock type for the KeyLister type
+type MockKeyLister struct {
+	mock.Mock
+}
+
+type MockKeyLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockKeyLister) EXPECT() *MockKeyLister_Expecter {
+	return &MockKeyLister_Expecter{mock: &_m.Mock}
+}
+
+// GetUser provides a mock function for the type MockKeyLister
+func (_mock *MockKeyLister) GetUser(ctx context.Context, name string) (storage.User, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 storage.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (storage.User, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) storage.User); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		r0 = ret.Get(0).(storage.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKeyLister_GetUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUser'
+type MockKeyLister_GetUser_Call struct {
+	*mock.Call
+}
+
+// GetUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockKeyLister_Expecter) GetUser(ctx interface{}, name interface{}) *MockKeyLister_GetUser_Call {
+	return &MockKeyLister_GetUser_Call{Call: _e.mock.On("GetUser", ctx, name)}
+}
+
+func (_c *MockKeyLister_GetUser_Call) Run(run func(ctx context.Context, name string)) *MockKeyLister_GetUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKeyLister_GetUser_Call) Return(user storage.User, err error) *MockKeyLister_GetUser_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockKeyLister_GetUser_Call) RunAndReturn(run func(ctx context.Context, name string) (storage.User, error)) *MockKeyLister_GetUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAPIKeys provides a mock function for the type MockKeyLister
+func (_mock *MockKeyLister) ListAPIKeys(ctx context.Context, userID int64) ([]storage.APIKey, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAPIKeys")
+	}
+
+	var r0 []storage.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) ([]storage.APIKey, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) []storage.APIKey); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKeyLister_ListAPIKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAPIKeys'
+type MockKeyLister_ListAPIKeys_Call struct {
+	*mock.Call
+}
+
+// ListAPIKeys is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *MockKeyLister_Expecter) ListAPIKeys(ctx interface{}, userID interface{}) *MockKeyLister_ListAPIKeys_Call {
+	return &MockKeyLister_ListAPIKeys_Call{Call: _e.mock.On("ListAPIKeys", ctx, userID)}
+}
+
+func (_c *MockKeyLister_ListAPIKeys_Call) Run(run func(ctx context.Context, userID int64)) *MockKeyLister_ListAPIKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKeyLister_ListAPIKeys_Call) Return(aPIKeys []storage.APIKey, err error) *MockKeyLister_ListAPIKeys_Call {
+	_c.Call.Return(aPIKeys, err)
+	return _c
+}
+
+func (_c *MockKeyLister_ListAPIKeys_Call) RunAndReturn(run func(ctx context.Context, userID int64) ([]storage.APIKey, error)) *MockKeyLister_ListAPIKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package apikey_mocks
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockKeyRevoker creates a new instance of MockKeyRevoker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockKeyRevoker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockKeyRevoker {
+	mock := &MockKeyRevoker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockKeyRevoker is an autogenerated mock type for the KeyRevoker type
+type MockKeyRevoker struct {
+	mock.Mock
+}
+
+type MockKeyRevoker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockKeyRevoker) EXPECT() *MockKeyRevoker_Expecter {
+	return &MockKeyRevoker_Expecter{mock: &_m.Mock}
+}
+
+// RevokeAPIKey provides a mock function for the type MockKeyRevoker
+func (_mock *MockKeyRevoker) RevokeAPIKey(ctx context.Context, id int64) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKey")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockKeyRevoker_RevokeAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAPIKey'
+type MockKeyRevoker_RevokeAPIKey_Call struct {
+	*mock.Call
+}
+
+// RevokeAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockKeyRevoker_Expecter) RevokeAPIKey(ctx interface{}, id interface{}) *MockKeyRevoker_RevokeAPIKey_Call {
+	return &MockKeyRevoker_RevokeAPIKey_Call{Call: _e.mock.On("RevokeAPIKey", ctx, id)}
+}
+
+func (_c *MockKeyRevoker_RevokeAPIKey_Call) Run(run func(ctx context.Context, id int64)) *MockKeyRevoker_RevokeAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKeyRevoker_RevokeAPIKey_Call) Return(err error) *MockKeyRevoker_RevokeAPIKey_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockKeyRevoker_RevokeAPIKey_Call) RunAndReturn(run func(ctx context.Context, id int64) error) *MockKeyRevoker_RevokeAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,105 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package apikey_mocks
+
+import (
+	"context"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockUserCreator creates a new instance of MockUserCreator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserCreator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserCreator {
+	mock := &MockUserCreator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUserCreator is an autogenerated mock type for the UserCreator type
+type MockUserCreator struct {
+	mock.Mock
+}
+
+type MockUserCreator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserCreator) EXPECT() *MockUserCreator_Expecter {
+	return &MockUserCreator_Expecter{mock: &_m.Mock}
+}
+
+// CreateUser provides a mock function for the type MockUserCreator
+func (_mock *MockUserCreator) CreateUser(ctx context.Context, name string) (storage.User, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 storage.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (storage.User, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) storage.User); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		r0 = ret.Get(0).(storage.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserCreator_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
+type MockUserCreator_CreateUser_Call struct {
+	*mock.Call
+}
+
+// CreateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockUserCreator_Expecter) CreateUser(ctx interface{}, name interface{}) *MockUserCreator_CreateUser_Call {
+	return &MockUserCreator_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, name)}
+}
+
+func (_c *MockUserCreator_CreateUser_Call) Run(run func(ctx context.Context, name string)) *MockUserCreator_CreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserCreator_CreateUser_Call) Return(user storage.User, err error) *MockUserCreator_CreateUser_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserCreator_CreateUser_Call) RunAndReturn(run func(ctx context.Context, name string) (storage.User, error)) *MockUserCreator_CreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,201 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+	"github.com/n0f4ph4mst3r/goshort/internal/transfer"
+)
+
+type ImportResponse struct {
+	response.Message
+	transfer.Report
+}
+
+type Exporter interface {
+	Export(ctx context.Context, w io.Writer, format transfer.Format) (int, error)
+}
+
+type Importer interface {
+	Import(ctx context.Context, r io.Reader, format transfer.Format, opts transfer.ImportOptions) (transfer.Report, error)
+}
+
+var (
+	errInvalidFormat   = errors.New("parameter format must be csv or ndjson")
+	errInvalidStrategy = errors.New("parameter strategy must be skip, overwrite or fail")
+	errInvalidDryRun   = errors.New("parameter dry_run must be a boolean")
+)
+
+// NewExport streams all live links as a CSV or NDJSON attachment, picked
+// by the format parameter (ndjson by default). Exports of large databases
+// outlast the server write timeout, so the deadline is lifted for them.
+func NewExport(log *slog.Logger, exporter Exporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.backup.NewExport")
+
+		format, err := transfer.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error(errInvalidFormat.Error()),
+				"invalid export format", sl.Err(err))
+
+			return
+		}
+
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			log.Warn("failed to lift write deadline", sl.Err(err))
+		}
+
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", `attachment; filename="goshort-export.`+string(format)+`"`)
+
+		out := &trackingWriter{w: w}
+		n, err := exporter.Export(r.Context(), out, format)
+		if err != nil && !out.written {
+			w.Header().Del("Content-Disposition")
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to export links", sl.Err(err))
+
+			return
+		}
+		// The status line is out once the first row is written, so a
+		// failure past that point can only be logged.
+		if err != nil {
+			log.Error("export interrupted", slog.Int("count", n), sl.Err(err))
+			return
+		}
+
+		log.Info("links exported", slog.Int("count", n), slog.String("format", string(format)))
+	}
+}
+
+// trackingWriter records whether anything was written through it.
+type trackingWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = true
+	return t.w.Write(p)
+}
+
+// NewImport loads links from the request body. The format parameter
+// defaults to csv for text/csv bodies and to ndjson otherwise; strategy
+// decides what happens to taken aliases and dry_run only reports. Bodies
+// and record counts over the configured caps are refused with 413; within
+// them the read deadline is lifted, like the write deadline of exports.
+func NewImport(log *slog.Logger, importer Importer, cfg *config.ImportConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.backup.NewImport")
+
+		query := r.URL.Query()
+
+		rawFormat := query.Get("format")
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); rawFormat == "" && mediaType == "text/csv" {
+			rawFormat = string(transfer.FormatCSV)
+		}
+		format, err := transfer.ParseFormat(rawFormat)
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error(errInvalidFormat.Error()),
+				"invalid import format", sl.Err(err))
+
+			return
+		}
+
+		strategy, err := transfer.ParseStrategy(query.Get("strategy"))
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error(errInvalidStrategy.Error()),
+				"invalid conflict strategy", sl.Err(err))
+
+			return
+		}
+
+		var dryRun bool
+		if raw := query.Get("dry_run"); raw != "" {
+			if dryRun, err = strconv.ParseBool(raw); err != nil {
+				sl.WriteResponse(log, w, r, http.StatusBadRequest,
+					response.Error(errInvalidDryRun.Error()),
+					"invalid dry run flag", sl.Err(err))
+
+				return
+			}
+		}
+
+		if err := http.NewResponseController(w).SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			log.Warn("failed to lift read deadline", sl.Err(err))
+		}
+
+		body := r.Body
+		if cfg.MaxBytes > 0 {
+			body = http.MaxBytesReader(w, r.Body, cfg.MaxBytes)
+		}
+
+		report, err := importer.Import(r.Context(), body, format, transfer.ImportOptions{
+			Strategy:   strategy,
+			DryRun:     dryRun,
+			MaxRecords: cfg.MaxRecords,
+		})
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			sl.WriteResponse(log, w, r, http.StatusRequestEntityTooLarge,
+				response.Error(fmt.Sprintf("import file must be at most %d bytes", maxBytesErr.Limit)),
+				"import file too large", sl.Err(err))
+
+			return
+		}
+		if errors.Is(err, transfer.ErrTooLarge) {
+			sl.WriteResponse(log, w, r, http.StatusRequestEntityTooLarge,
+				response.Error(fmt.Sprintf("import file must contain at most %d records", cfg.MaxRecords)),
+				"import file too large", sl.Err(err))
+
+			return
+		}
+
+		var parseErr *transfer.ParseError
+		if errors.As(err, &parseErr) {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("invalid import file: "+parseErr.Error()),
+				"failed to parse import file", sl.Err(err))
+
+			return
+		}
+		if errors.Is(err, transfer.ErrConflict) {
+			sl.WriteResponse(log, w, r, http.StatusConflict, ImportResponse{
+				Message: response.Error("aliases already exist"),
+				Report:  report,
+			}, "import aborted on conflicts", slog.Int("conflicts", len(report.Conflicts)))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to import links", sl.Err(err))
+
+			return
+		}
+
+		sl.WriteResponse(log, w, r, 0, ImportResponse{
+			Message: response.OK(),
+			Report:  report,
+		}, "links imported",
+			slog.Int("created", report.Created), slog.Int("updated", report.Updated),
+			slog.Int("skipped", report.Skipped), slog.Int("failed", report.Failed),
+			slog.Bool("dry_run", dryRun))
+	}
+}
@@ -0,0 +1,269 @@
+package backup_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/backup"
+	mocks "github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/backup/mocks"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+	"github.com/n0f4ph4mst3r/goshort/internal/transfer"
+)
+
+func TestExportHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		query        string
+		format       transfer.Format
+		expectedCode int
+		contentType  string
+		respError    string
+	}{
+		{
+			name:         "Default format",
+			format:       transfer.FormatNDJSON,
+			expectedCode: http.StatusOK,
+			contentType:  "application/x-ndjson",
+		},
+		{
+			name:         "CSV",
+			query:        "?format=csv",
+			format:       transfer.FormatCSV,
+			expectedCode: http.StatusOK,
+			contentType:  "text/csv",
+		},
+		{
+			name:         "Unknown format",
+			query:        "?format=xml",
+			expectedCode: http.StatusBadRequest,
+			respError:    "parameter format must be csv or ndjson",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exporterMock := mocks.NewMockExporter(t)
+			if tc.format != "" {
+				exporterMock.On("Export", mock.Anything, mock.Anything, tc.format).
+					Run(func(args mock.Arguments) {
+						_, _ = io.WriteString(args.Get(1).(io.Writer), "row\n")
+					}).
+					Return(1, nil).Once()
+			}
+
+			handler := backup.NewExport(sldiscard.NewDiscardLogger(), exporterMock)
+
+			req, err := http.NewRequest(http.MethodGet, "/api/backup/export"+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.respError != "" {
+				var resp backup.ImportResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tc.respError, resp.Error)
+				return
+			}
+
+			require.Equal(t, tc.contentType, rr.Header().Get("Content-Type"))
+			require.Equal(t, fmt.Sprintf(`attachment; filename="goshort-export.%s"`, tc.format), rr.Header().Get("Content-Disposition"))
+			require.Equal(t, "row\n", rr.Body.String())
+		})
+	}
+}
+
+func TestExportHandler_Failure(t *testing.T) {
+	cases := []struct {
+		name         string
+		written      string
+		expectedCode int
+	}{
+		{name: "Before the first row", expectedCode: http.StatusInternalServerError},
+		{name: "Midway", written: "row\n", expectedCode: http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exporterMock := mocks.NewMockExporter(t)
+			exporterMock.On("Export", mock.Anything, mock.Anything, transfer.FormatNDJSON).
+				Run(func(args mock.Arguments) {
+					if tc.written != "" {
+						_, _ = io.WriteString(args.Get(1).(io.Writer), tc.written)
+					}
+				}).
+				Return(0, errors.New("connection reset")).Once()
+
+			handler := backup.NewExport(sldiscard.NewDiscardLogger(), exporterMock)
+
+			req, err := http.NewRequest(http.MethodGet, "/api/backup/export", nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusInternalServerError {
+				require.Empty(t, rr.Header().Get("Content-Disposition"))
+
+				var resp backup.ImportResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, "internal error", resp.Error)
+				return
+			}
+			require.Equal(t, tc.written, rr.Body.String())
+		})
+	}
+}
+
+func TestImportHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		query        string
+		contentType  string
+		format       transfer.Format
+		opts         transfer.ImportOptions
+		report       transfer.Report
+		mockError    error
+		expectedCode int
+		respError    string
+	}{
+		{
+			name:         "Success",
+			format:       transfer.FormatNDJSON,
+			opts:         transfer.ImportOptions{Strategy: transfer.StrategySkip, MaxRecords: 100},
+			report:       transfer.Report{Total: 2, Created: 1, Skipped: 1},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "CSV by content type, dry run",
+			query:        "?strategy=overwrite&dry_run=true",
+			contentType:  "text/csv; charset=utf-8",
+			format:       transfer.FormatCSV,
+			opts:         transfer.ImportOptions{Strategy: transfer.StrategyOverwrite, DryRun: true, MaxRecords: 100},
+			report:       transfer.Report{Total: 1, Updated: 1, DryRun: true},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Conflicts",
+			query:        "?strategy=fail",
+			format:       transfer.FormatNDJSON,
+			opts:         transfer.ImportOptions{Strategy: transfer.StrategyFail, MaxRecords: 100},
+			report:       transfer.Report{Total: 1, Failed: 1, Conflicts: []string{"GoDuck"}},
+			mockError:    fmt.Errorf("transfer.Import: %w: 1", transfer.ErrConflict),
+			expectedCode: http.StatusConflict,
+			respError:    "aliases already exist",
+		},
+		{
+			name:         "Malformed file",
+			format:       transfer.FormatNDJSON,
+			opts:         transfer.ImportOptions{Strategy: transfer.StrategySkip, MaxRecords: 100},
+			mockError:    fmt.Errorf("transfer.Import: %w", &transfer.ParseError{Line: 3, Err: errors.New("unexpected EOF")}),
+			expectedCode: http.StatusBadRequest,
+			respError:    "invalid import file: line 3: unexpected EOF",
+		},
+		{
+			name:         "Too many records",
+			format:       transfer.FormatNDJSON,
+			opts:         transfer.ImportOptions{Strategy: transfer.StrategySkip, MaxRecords: 100},
+			mockError:    fmt.Errorf("transfer.Import: %w: more than 100", transfer.ErrTooLarge),
+			expectedCode: http.StatusRequestEntityTooLarge,
+			respError:    "import file must contain at most 100 records",
+		},
+		{
+			name:         "Storage failure",
+			format:       transfer.FormatNDJSON,
+			opts:         transfer.ImportOptions{Strategy: transfer.StrategySkip, MaxRecords: 100},
+			mockError:    errors.New("connection reset"),
+			expectedCode: http.StatusInternalServerError,
+			respError:    "internal error",
+		},
+		{
+			name:         "Unknown strategy",
+			query:        "?strategy=merge",
+			expectedCode: http.StatusBadRequest,
+			respError:    "parameter strategy must be skip, overwrite or fail",
+		},
+		{
+			name:         "Invalid dry run",
+			query:        "?dry_run=maybe",
+			expectedCode: http.StatusBadRequest,
+			respError:    "parameter dry_run must be a boolean",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			importerMock := mocks.NewMockImporter(t)
+			if tc.format != "" {
+				importerMock.On("Import", mock.Anything, mock.Anything, tc.format, tc.opts).
+					Return(tc.report, tc.mockError).Once()
+			}
+
+			handler := backup.NewImport(sldiscard.NewDiscardLogger(), importerMock, &config.ImportConfig{MaxBytes: 1 << 20, MaxRecords: 100})
+
+			req, err := http.NewRequest(http.MethodPost, "/api/backup/import"+tc.query, strings.NewReader("data"))
+			require.NoError(t, err)
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			var resp backup.ImportResponse
+			require.Equal(t, tc.expectedCode, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+			if tc.mockError == nil || len(tc.report.Conflicts) > 0 {
+				require.Equal(t, tc.report, resp.Report)
+			}
+		})
+	}
+}
+
+func TestImportHandler_BodyTooLarge(t *testing.T) {
+	importerMock := mocks.NewMockImporter(t)
+	importerMock.EXPECT().Import(mock.Anything, mock.Anything, transfer.FormatNDJSON, mock.Anything).
+		RunAndReturn(func(_ context.Context, r io.Reader, _ transfer.Format, _ transfer.ImportOptions) (transfer.Report, error) {
+			_, err := io.ReadAll(r)
+			return transfer.Report{}, err
+		}).Once()
+
+	handler := backup.NewImport(sldiscard.NewDiscardLogger(), importerMock, &config.ImportConfig{MaxBytes: 8})
+
+	req, err := http.NewRequest(http.MethodPost, "/api/backup/import", strings.NewReader(`{"alias": "GoDuck"}`))
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var resp backup.ImportResponse
+	require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, "import file must be at most 8 bytes", resp.Error)
+}
@@ -0,0 +1,112 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package backup_mocks
+
+import (
+	"context"
+	"io"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/transfer"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockExporter creates a new instance of MockExporter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockExporter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockExporter {
+	mock := &MockExporter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockExporter is an autogenerated mock type for the Exporter type
+type MockExporter struct {
+	mock.Mock
+}
+
+type MockExporter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockExporter) EXPECT() *MockExporter_Expecter {
+	return &MockExporter_Expecter{mock: &_m.Mock}
+}
+
+// Export provides a mock function for the type MockExporter
+func (_mock *MockExporter) Export(ctx context.Context, w io.Writer, format transfer.Format) (int, error) {
+	ret := _mock.Called(ctx, w, format)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, io.Writer, transfer.Format) (int, error)); ok {
+		return returnFunc(ctx, w, format)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, io.Writer, transfer.Format) int); ok {
+		r0 = returnFunc(ctx, w, format)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, io.Writer, transfer.Format) error); ok {
+		r1 = returnFunc(ctx, w, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockExporter_Export_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Export'
+type MockExporter_Export_Call struct {
+	*mock.Call
+}
+
+// Export is a helper method to define mock.On call
+//   - ctx context.Context
+//   - w io.Writer
+//   - format transfer.Format
+func (_e *MockExporter_Expecter) Export(ctx interface{}, w interface{}, format interface{}) *MockExporter_Export_Call {
+	return &MockExporter_Export_Call{Call: _e.mock.On("Export", ctx, w, format)}
+}
+
+func (_c *MockExporter_Export_Call) Run(run func(ctx context.Context, w io.Writer, format transfer.Format)) *MockExporter_Export_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 io.Writer
+		if args[1] != nil {
+			arg1 = args[1].(io.Writer)
+		}
+		var arg2 transfer.Format
+		if args[2] != nil {
+			arg2 = args[2].(transfer.Format)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockExporter_Export_Call) Return(n int, err error) *MockExporter_Export_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockExporter_Export_Call) RunAndReturn(run func(ctx context.Context, w io.Writer, format transfer.Format) (int, error)) *MockExporter_Export_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,118 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package backup_mocks
+
+import (
+	"context"
+	"io"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/transfer"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockImporter creates a new instance of MockImporter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockImporter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockImporter {
+	mock := &MockImporter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockImporter is an autogenerated mock type for the Importer type
+type MockImporter struct {
+	mock.Mock
+}
+
+type MockImporter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockImporter) EXPECT() *MockImporter_Expecter {
+	return &MockImporter_Expecter{mock: &_m.Mock}
+}
+
+// Import provides a mock function for the type MockImporter
+func (_mock *MockImporter) Import(ctx context.Context, r io.Reader, format transfer.Format, opts transfer.ImportOptions) (transfer.Report, error) {
+	ret := _mock.Called(ctx, r, format, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 transfer.Report
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, io.Reader, transfer.Format, transfer.ImportOptions) (transfer.Report, error)); ok {
+		return returnFunc(ctx, r, format, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, io.Reader, transfer.Format, transfer.ImportOptions) transfer.Report); ok {
+		r0 = returnFunc(ctx, r, format, opts)
+	} else {
+		r0 = ret.Get(0).(transfer.Report)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, io.Reader, transfer.Format, transfer.ImportOptions) error); ok {
+		r1 = returnFunc(ctx, r, format, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockImporter_Import_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Import'
+type MockImporter_Import_Call struct {
+	*mock.Call
+}
+
+// Import is a helper method to define mock.On call
+//   - ctx context.Context
+//   - r io.Reader
+//   - format transfer.Format
+//   - opts transfer.ImportOptions
+func (_e *MockImporter_Expecter) Import(ctx interface{}, r interface{}, format interface{}, opts interface{}) *MockImporter_Import_Call {
+	return &MockImporter_Import_Call{Call: _e.mock.On("Import", ctx, r, format, opts)}
+}
+
+func (_c *MockImporter_Import_Call) Run(run func(ctx context.Context, r io.Reader, format transfer.Format, opts transfer.ImportOptions)) *MockImporter_Import_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 io.Reader
+		if args[1] != nil {
+			arg1 = args[1].(io.Reader)
+		}
+		var arg2 transfer.Format
+		if args[2] != nil {
+			arg2 = args[2].(transfer.Format)
+		}
+		var arg3 transfer.ImportOptions
+		if args[3] != nil {
+			arg3 = args[3].(transfer.ImportOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockImporter_Import_Call) Return(report transfer.Report, err error) *MockImporter_Import_Call {
+	_c.Call.Return(report, err)
+	return _c
+}
+
+func (_c *MockImporter_Import_Call) RunAndReturn(run func(ctx context.Context, r io.Reader, format transfer.Format, opts transfer.ImportOptions) (transfer.Report, error)) *MockImporter_Import_Call {
+	_c.Call.Return(run)
+	return _c
+}
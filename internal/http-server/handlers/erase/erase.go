@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
@@ -59,3 +60,115 @@ func New(log *slog.Logger, urlEraser UrlEraser) http.HandlerFunc {
 			"URL deleted", slog.String("url", url))
 	}
 }
+
+type ByOriginResponse struct {
+	response.Message
+	URL     string   `json:"url,omitempty"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+type OriginEraser interface {
+	DeleteByOrigin(ctx context.Context, u string) ([]string, error)
+}
+
+type UrlCanonicalizer interface {
+	Canonicalize(raw string) (string, error)
+}
+
+// NewByOrigin deletes every alias of a destination. The origin is
+// canonicalized the same way as on save, so any spelling of it matches;
+// an origin that cannot be canonicalized is looked up as is.
+func NewByOrigin(log *slog.Logger, originEraser OriginEraser, canonicalizer UrlCanonicalizer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.url.erase.NewByOrigin")
+
+		origin := r.URL.Query().Get("origin")
+		if origin == "" {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("invalid request"),
+				"origin is empty")
+
+			return
+		}
+		if canonicalizer != nil {
+			if canonical, err := canonicalizer.Canonicalize(origin); err == nil {
+				origin = canonical
+			}
+		}
+
+		aliases, err := originEraser.DeleteByOrigin(r.Context(), origin)
+		if errors.Is(err, storage.ErrUrlNotFound) {
+			sl.WriteResponse(log, w, r, http.StatusNotFound,
+				response.Error("invalid request"),
+				"URL not found", slog.String("url", origin))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to delete URLs", sl.Err(err))
+
+			return
+		}
+
+		sl.WriteResponse(log, w, r, 0,
+			ByOriginResponse{
+				Message: response.OK(),
+				URL:     origin,
+				Aliases: aliases,
+			},
+			"URLs deleted", slog.String("url", origin), slog.Any("aliases", aliases))
+	}
+}
+
+type RestoreResponse struct {
+	response.Message
+	Alias     string     `json:"alias,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type UrlRestorer interface {
+	RestoreURL(ctx context.Context, alias string) (storage.Link, error)
+}
+
+func NewRestore(log *slog.Logger, urlRestorer UrlRestorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.url.erase.NewRestore")
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("invalid request"),
+				"alias is empty")
+
+			return
+		}
+
+		link, err := urlRestorer.RestoreURL(r.Context(), alias)
+		if errors.Is(err, storage.ErrUrlNotFound) {
+			sl.WriteResponse(log, w, r, http.StatusNotFound,
+				response.Error("invalid request"),
+				"URL not found in trash", slog.String("alias", alias))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to restore URL", sl.Err(err))
+
+			return
+		}
+
+		sl.WriteResponse(log, w, r, 0,
+			RestoreResponse{
+				Message:   response.OK(),
+				Alias:     alias,
+				URL:       link.URL,
+				ExpiresAt: link.ExpiresAt,
+			},
+			"URL restored", slog.String("alias", alias), slog.String("url", link.URL))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -90,3 +91,125 @@ func TestEraseHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestEraseByOriginHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		origin       string
+		mockAliases  []string
+		expectedCode int
+		mockError    error
+	}{
+		{
+			name:         "Success",
+			origin:       "https://duckduckgo.com",
+			mockAliases:  []string{"duck", "GoDuck"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Empty origin",
+			origin:       "",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "URL Not Found",
+			origin:       "https://duckduckgo.com",
+			expectedCode: http.StatusNotFound,
+			mockError:    storage.ErrUrlNotFound,
+		},
+		{
+			name:         "DeleteByOrigin Error",
+			origin:       "https://duckduckgo.com",
+			expectedCode: http.StatusInternalServerError,
+			mockError:    errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			originEraserMock := mocks.NewMockOriginEraser(t)
+
+			if tc.expectedCode != http.StatusBadRequest {
+				originEraserMock.On("DeleteByOrigin", mock.Anything, tc.origin).
+					Return(tc.mockAliases, tc.mockError).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Delete("/url", erase.NewByOrigin(sldiscard.NewDiscardLogger(), originEraserMock, nil))
+
+			req, err := http.NewRequest(http.MethodDelete, "/url?origin="+url.QueryEscape(tc.origin), nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusOK {
+				var resp erase.ByOriginResponse
+				body := rr.Body.String()
+				require.NoError(t, json.Unmarshal([]byte(body), &resp))
+				require.Equal(t, tc.origin, resp.URL)
+				require.Equal(t, tc.mockAliases, resp.Aliases)
+			}
+		})
+	}
+}
+
+func TestRestoreHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		alias        string
+		mockLink     storage.Link
+		expectedCode int
+		mockError    error
+	}{
+		{
+			name:         "Success",
+			alias:        "some_alias",
+			mockLink:     storage.Link{Alias: "some_alias", URL: "https://duckduckgo.com"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Not in trash",
+			alias:        "some_alias",
+			expectedCode: http.StatusNotFound,
+			mockError:    storage.ErrUrlNotFound,
+		},
+		{
+			name:         "RestoreURL Error",
+			alias:        "some_alias",
+			expectedCode: http.StatusInternalServerError,
+			mockError:    errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlRestorerMock := mocks.NewMockUrlRestorer(t)
+			urlRestorerMock.On("RestoreURL", mock.Anything, tc.alias).
+				Return(tc.mockLink, tc.mockError).Once()
+
+			router := chi.NewRouter()
+			router.Post("/url/{alias}/restore", erase.NewRestore(sldiscard.NewDiscardLogger(), urlRestorerMock))
+
+			req, err := http.NewRequest(http.MethodPost, "/url/"+tc.alias+"/restore", nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusOK {
+				var resp erase.RestoreResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tc.mockLink.URL, resp.URL)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package erase_mocks
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockOriginEraser creates a new instance of MockOriginEraser. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOriginEraser(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOriginEraser {
+	mock := &MockOriginEraser{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockOriginEraser is an autogenerated mock type for the OriginEraser type
+type MockOriginEraser struct {
+	mock.Mock
+}
+
+type MockOriginEraser_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOriginEraser) EXPECT() *MockOriginEraser_Expecter {
+	return &MockOriginEraser_Expecter{mock: &_m.Mock}
+}
+
+// DeleteByOrigin provides a mock function for the type MockOriginEraser
+func (_mock *MockOriginEraser) DeleteByOrigin(ctx context.Context, u string) ([]string, error) {
+	ret := _mock.Called(ctx, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByOrigin")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return returnFunc(ctx, u)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = returnFunc(ctx, u)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, u)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockOriginEraser_DeleteByOrigin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteByOrigin'
+type MockOriginEraser_DeleteByOrigin_Call struct {
+	*mock.Call
+}
+
+// DeleteByOrigin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - u string
+func (_e *MockOriginEraser_Expecter) DeleteByOrigin(ctx interface{}, u interface{}) *MockOriginEraser_DeleteByOrigin_Call {
+	return &MockOriginEraser_DeleteByOrigin_Call{Call: _e.mock.On("DeleteByOrigin", ctx, u)}
+}
+
+func (_c *MockOriginEraser_DeleteByOrigin_Call) Run(run func(ctx context.Context, u string)) *MockOriginEraser_DeleteByOrigin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockOriginEraser_DeleteByOrigin_Call) Return(strings []string, err error) *MockOriginEraser_DeleteByOrigin_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockOriginEraser_DeleteByOrigin_Call) RunAndReturn(run func(ctx context.Context, u string) ([]string, error)) *MockOriginEraser_DeleteByOrigin_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,105 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package erase_mocks
+
+import (
+	"context"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockUrlRestorer creates a new instance of MockUrlRestorer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUrlRestorer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUrlRestorer {
+	mock := &MockUrlRestorer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUrlRestorer is an autogenerated mock type for the UrlRestorer type
+type MockUrlRestorer struct {
+	mock.Mock
+}
+
+type MockUrlRestorer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUrlRestorer) EXPECT() *MockUrlRestorer_Expecter {
+	return &MockUrlRestorer_Expecter{mock: &_m.Mock}
+}
+
+// RestoreURL provides a mock function for the type MockUrlRestorer
+func (_mock *MockUrlRestorer) RestoreURL(ctx context.Context, alias string) (storage.Link, error) {
+	ret := _mock.Called(ctx, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreURL")
+	}
+
+	var r0 storage.Link
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (storage.Link, error)); ok {
+		return returnFunc(ctx, alias)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) storage.Link); ok {
+		r0 = returnFunc(ctx, alias)
+	} else {
+		r0 = ret.Get(0).(storage.Link)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUrlRestorer_RestoreURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreURL'
+type MockUrlRestorer_RestoreURL_Call struct {
+	*mock.Call
+}
+
+// RestoreURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+func (_e *MockUrlRestorer_Expecter) RestoreURL(ctx interface{}, alias interface{}) *MockUrlRestorer_RestoreURL_Call {
+	return &MockUrlRestorer_RestoreURL_Call{Call: _e.mock.On("RestoreURL", ctx, alias)}
+}
+
+func (_c *MockUrlRestorer_RestoreURL_Call) Run(run func(ctx context.Context, alias string)) *MockUrlRestorer_RestoreURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUrlRestorer_RestoreURL_Call) Return(link storage.Link, err error) *MockUrlRestorer_RestoreURL_Call {
+	_c.Call.Return(link, err)
+	return _c
+}
+
+func (_c *MockUrlRestorer_RestoreURL_Call) RunAndReturn(run func(ctx context.Context, alias string) (storage.Link, error)) *MockUrlRestorer_RestoreURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,220 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+type Response struct {
+	response.Message
+	Alias    string           `json:"alias,omitempty"`
+	Versions []storage.Change `json:"versions"`
+}
+
+type HistoryGetter interface {
+	GetHistory(ctx context.Context, alias string) ([]storage.Change, error)
+}
+
+func New(log *slog.Logger, historyGetter HistoryGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.url.history.New")
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("invalid request"),
+				"alias is empty")
+
+			return
+		}
+
+		versions, err := historyGetter.GetHistory(r.Context(), alias)
+		if errors.Is(err, storage.ErrUrlNotFound) {
+			sl.WriteResponse(log, w, r, http.StatusNotFound,
+				response.Error("invalid request"),
+				"URL not found", slog.String("alias", alias))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to get history", sl.Err(err))
+
+			return
+		}
+
+		if versions == nil {
+			versions = []storage.Change{}
+		}
+
+		sl.WriteResponse(log, w, r, 0,
+			Response{
+				Message:  response.OK(),
+				Alias:    alias,
+				Versions: versions,
+			},
+			"history retrieved", slog.String("alias", alias), slog.Int("versions", len(versions)))
+	}
+}
+
+type RollbackRequest struct {
+	Version *int64 `json:"version" validate:"required,min=0"`
+}
+
+type RollbackResponse struct {
+	response.Message
+	Alias     string     `json:"alias,omitempty"`
+	Version   int64      `json:"version,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type Rollbacker interface {
+	Version(ctx context.Context, alias string, version int64) (storage.Link, error)
+	UpdateURL(ctx context.Context, upd storage.Update) (storage.Change, error)
+}
+
+type DestinationPolicy interface {
+	Check(ctx context.Context, raw string) error
+}
+
+// NewRollback returns the handler restoring the destination and expiration
+// an alias had at an earlier version. The restored destination is vetted
+// like a new one, and versions whose expiration has passed are refused. A
+// nil destination policy accepts any destination.
+func NewRollback(log *slog.Logger, rollbacker Rollbacker, destinations DestinationPolicy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.url.history.NewRollback")
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("invalid request"),
+				"alias is empty")
+
+			return
+		}
+
+		var req RollbackRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if errors.Is(err, io.EOF) {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("empty request"),
+				"request body is empty")
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("invalid request body"),
+				"failed to decode request body", sl.Err(err))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.ValidationError(validateErr),
+				"request validation failed", sl.Err(validateErr))
+
+			return
+		}
+
+		target, err := rollbacker.Version(r.Context(), alias, *req.Version)
+		if errors.Is(err, storage.ErrUrlNotFound) || errors.Is(err, storage.ErrUrlDeleted) {
+			sl.WriteResponse(log, w, r, http.StatusNotFound,
+				response.Error("invalid request"),
+				"URL not found", slog.String("alias", alias))
+
+			return
+		}
+		if errors.Is(err, storage.ErrVersionNotFound) {
+			sl.WriteResponse(log, w, r, http.StatusNotFound,
+				response.Error("version not found"),
+				"version not found", slog.String("alias", alias), slog.Int64("version", *req.Version))
+
+			return
+		}
+		if errors.Is(err, storage.ErrUrlExpired) {
+			sl.WriteResponse(log, w, r, http.StatusUnprocessableEntity,
+				response.Error("expiration of the version has passed"),
+				"version expired", slog.String("alias", alias), slog.Int64("version", *req.Version))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to get version", sl.Err(err))
+
+			return
+		}
+
+		if destinations != nil {
+			if err := destinations.Check(r.Context(), target.URL); err != nil {
+				sl.WriteResponse(log, w, r, http.StatusUnprocessableEntity,
+					response.Error(err.Error()),
+					"destination rejected by policy", slog.String("url", target.URL), sl.Err(err))
+
+				return
+			}
+		}
+
+		if target.ExpiresAt != nil && !target.ExpiresAt.After(time.Now()) {
+			sl.WriteResponse(log, w, r, http.StatusUnprocessableEntity,
+				response.Error("expiration of the version has passed"),
+				"version expired", slog.String("alias", alias), slog.Int64("version", *req.Version))
+
+			return
+		}
+
+		principal, _ := auth.PrincipalFrom(r.Context())
+		change, err := rollbacker.UpdateURL(r.Context(), storage.Update{
+			Alias:          alias,
+			URL:            target.URL,
+			ExpiresAt:      target.ExpiresAt,
+			ClearExpiresAt: target.ExpiresAt == nil,
+			ChangedBy:      principal.Name,
+		})
+		if errors.Is(err, storage.ErrUrlNotFound) {
+			sl.WriteResponse(log, w, r, http.StatusNotFound,
+				response.Error("invalid request"),
+				"URL not found", slog.String("alias", alias))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to roll back URL", sl.Err(err))
+
+			return
+		}
+
+		sl.WriteResponse(log, w, r, 0,
+			RollbackResponse{
+				Message:   response.OK(),
+				Alias:     alias,
+				Version:   change.Version,
+				URL:       change.NewURL,
+				ExpiresAt: change.NewExpiresAt,
+			},
+			"URL rolled back", slog.String("alias", alias),
+			slog.Int64("restored_version", *req.Version), slog.Int64("version", change.Version))
+	}
+}
@@ -0,0 +1,260 @@
+package history_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/destination"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/history"
+	mocks "github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/history/mocks"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+func TestHistoryHandler(t *testing.T) {
+	changedAt := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name         string
+		alias        string
+		mockVersions []storage.Change
+		expectedLen  int
+		expectedCode int
+		mockError    error
+	}{
+		{
+			name:  "Success",
+			alias: "some_alias",
+			mockVersions: []storage.Change{
+				{Version: 1, OldURL: "https://a.com", NewURL: "https://b.com", ChangedBy: "myuser", ChangedAt: changedAt},
+				{Version: 2, OldURL: "https://b.com", NewURL: "https://c.com", ChangedBy: "myuser", ChangedAt: changedAt},
+			},
+			expectedLen:  2,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "No changes",
+			alias:        "some_alias",
+			expectedLen:  0,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "URL Not Found",
+			alias:        "some_alias",
+			expectedCode: http.StatusNotFound,
+			mockError:    storage.ErrUrlNotFound,
+		},
+		{
+			name:         "GetHistory Error",
+			alias:        "some_alias",
+			expectedCode: http.StatusInternalServerError,
+			mockError:    errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			historyGetterMock := mocks.NewMockHistoryGetter(t)
+			historyGetterMock.On("GetHistory", mock.Anything, tc.alias).
+				Return(tc.mockVersions, tc.mockError).Once()
+
+			router := chi.NewRouter()
+			router.Get("/url/{alias}/history", history.New(sldiscard.NewDiscardLogger(), historyGetterMock))
+
+			req, err := http.NewRequest(http.MethodGet, "/url/"+tc.alias+"/history", nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusOK {
+				var resp history.Response
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Len(t, resp.Versions, tc.expectedLen)
+				require.NotNil(t, resp.Versions)
+			}
+		})
+	}
+}
+
+func TestRollbackHandler(t *testing.T) {
+	destinations, err := destination.NewPolicy(&config.DestinationConfig{Schemes: []string{"https"}}, nil)
+	require.NoError(t, err)
+
+	future := time.Now().Add(time.Hour).UTC()
+	past := time.Now().Add(-time.Hour).UTC()
+
+	cases := []struct {
+		name         string
+		alias        string
+		body         string
+		version      int64
+		mockLink     storage.Link
+		versionError error
+		mockChange   storage.Change
+		updateError  error
+		update       bool
+		expectedCode int
+	}{
+		{
+			name:         "Success",
+			alias:        "some_alias",
+			body:         `{"version": 1}`,
+			version:      1,
+			mockLink:     storage.Link{URL: "https://b.com", ExpiresAt: &future},
+			mockChange:   storage.Change{Version: 3, OldURL: "https://c.com", NewURL: "https://b.com"},
+			update:       true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Original version",
+			alias:        "some_alias",
+			body:         `{"version": 0}`,
+			version:      0,
+			mockLink:     storage.Link{URL: "https://a.com"},
+			mockChange:   storage.Change{Version: 3, OldURL: "https://c.com", NewURL: "https://a.com"},
+			update:       true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Empty body",
+			alias:        "some_alias",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Missing version",
+			alias:        "some_alias",
+			body:         `{}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Negative version",
+			alias:        "some_alias",
+			body:         `{"version": -1}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Version Not Found",
+			alias:        "some_alias",
+			body:         `{"version": 7}`,
+			version:      7,
+			versionError: storage.ErrVersionNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "URL Not Found",
+			alias:        "some_alias",
+			body:         `{"version": 1}`,
+			version:      1,
+			versionError: storage.ErrUrlNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "Expired link",
+			alias:        "some_alias",
+			body:         `{"version": 0}`,
+			version:      0,
+			versionError: storage.ErrUrlExpired,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "Version Error",
+			alias:        "some_alias",
+			body:         `{"version": 1}`,
+			version:      1,
+			versionError: errors.New("unexpected error"),
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "Destination rejected",
+			alias:        "some_alias",
+			body:         `{"version": 1}`,
+			version:      1,
+			mockLink:     storage.Link{URL: "http://127.0.0.1/admin"},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "Version expired",
+			alias:        "some_alias",
+			body:         `{"version": 1}`,
+			version:      1,
+			mockLink:     storage.Link{URL: "https://b.com", ExpiresAt: &past},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "Deleted meanwhile",
+			alias:        "some_alias",
+			body:         `{"version": 1}`,
+			version:      1,
+			mockLink:     storage.Link{URL: "https://b.com"},
+			updateError:  storage.ErrUrlNotFound,
+			update:       true,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "Rollback Error",
+			alias:        "some_alias",
+			body:         `{"version": 1}`,
+			version:      1,
+			mockLink:     storage.Link{URL: "https://b.com"},
+			updateError:  errors.New("unexpected error"),
+			update:       true,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rollbackerMock := mocks.NewMockRollbacker(t)
+			if tc.expectedCode != http.StatusBadRequest {
+				rollbackerMock.On("Version", mock.Anything, tc.alias, tc.version).
+					Return(tc.mockLink, tc.versionError).Once()
+			}
+			if tc.update {
+				rollbackerMock.On("UpdateURL", mock.Anything, storage.Update{
+					Alias:          tc.alias,
+					URL:            tc.mockLink.URL,
+					ExpiresAt:      tc.mockLink.ExpiresAt,
+					ClearExpiresAt: tc.mockLink.ExpiresAt == nil,
+					ChangedBy:      "myuser",
+				}).Return(tc.mockChange, tc.updateError).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Post("/url/{alias}/rollback", history.NewRollback(sldiscard.NewDiscardLogger(), rollbackerMock, destinations))
+
+			req, err := http.NewRequest(http.MethodPost, "/url/"+tc.alias+"/rollback", bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+			req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Name: "myuser"}))
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusOK {
+				var resp history.RollbackResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tc.mockChange.Version, resp.Version)
+				require.Equal(t, tc.mockChange.NewURL, resp.URL)
+			}
+		})
+	}
+}
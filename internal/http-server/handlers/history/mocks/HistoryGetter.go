@@ -0,0 +1,107 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package history_mocks
+
+import (
+	"context"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockHistoryGetter creates a new instance of MockHistoryGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockHistoryGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockHistoryGetter {
+	mock := &MockHistoryGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockHistoryGetter is an autogenerated mock type for the HistoryGetter type
+type MockHistoryGetter struct {
+	mock.Mock
+}
+
+type MockHistoryGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockHistoryGetter) EXPECT() *MockHistoryGetter_Expecter {
+	return &MockHistoryGetter_Expecter{mock: &_m.Mock}
+}
+
+// GetHistory provides a mock function for the type MockHistoryGetter
+func (_mock *MockHistoryGetter) GetHistory(ctx context.Context, alias string) ([]storage.Change, error) {
+	ret := _mock.Called(ctx, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHistory")
+	}
+
+	var r0 []storage.Change
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]storage.Change, error)); ok {
+		return returnFunc(ctx, alias)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []storage.Change); ok {
+		r0 = returnFunc(ctx, alias)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.Change)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockHistoryGetter_GetHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetHistory'
+type MockHistoryGetter_GetHistory_Call struct {
+	*mock.Call
+}
+
+// GetHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+func (_e *MockHistoryGetter_Expecter) GetHistory(ctx interface{}, alias interface{}) *MockHistoryGetter_GetHistory_Call {
+	return &MockHistoryGetter_GetHistory_Call{Call: _e.mock.On("GetHistory", ctx, alias)}
+}
+
+func (_c *MockHistoryGetter_GetHistory_Call) Run(run func(ctx context.Context, alias string)) *MockHistoryGetter_GetHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockHistoryGetter_GetHistory_Call) Return(changes []storage.Change, err error) *MockHistoryGetter_GetHistory_Call {
+	_c.Call.Return(changes, err)
+	return _c
+}
+
+func (_c *MockHistoryGetter_GetHistory_Call) RunAndReturn(run func(ctx context.Context, alias string) ([]storage.Change, error)) *MockHistoryGetter_GetHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
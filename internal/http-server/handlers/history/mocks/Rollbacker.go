@@ -0,0 +1,177 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package history_mocks
+
+import (
+	"context"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockRollbacker creates a new instance of MockRollbacker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRollbacker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRollbacker {
+	mock := &MockRollbacker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRollbacker is an autogenerated mock type for the Rollbacker type
+type MockRollbacker struct {
+	mock.Mock
+}
+
+type MockRollbacker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRollbacker) EXPECT() *MockRollbacker_Expecter {
+	return &MockRollbacker_Expecter{mock: &_m.Mock}
+}
+
+// UpdateURL provides a mock function for the type MockRollbacker
+func (_mock *MockRollbacker) UpdateURL(ctx context.Context, upd storage.Update) (storage.Change, error) {
+	ret := _mock.Called(ctx, upd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateURL")
+	}
+
+	var r0 storage.Change
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, storage.Update) (storage.Change, error)); ok {
+		return returnFunc(ctx, upd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, storage.Update) storage.Change); ok {
+		r0 = returnFunc(ctx, upd)
+	} else {
+		r0 = ret.Get(0).(storage.Change)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, storage.Update) error); ok {
+		r1 = returnFunc(ctx, upd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRollbacker_UpdateURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateURL'
+type MockRollbacker_UpdateURL_Call struct {
+	*mock.Call
+}
+
+// UpdateURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - upd storage.Update
+func (_e *MockRollbacker_Expecter) UpdateURL(ctx interface{}, upd interface{}) *MockRollbacker_UpdateURL_Call {
+	return &MockRollbacker_UpdateURL_Call{Call: _e.mock.On("UpdateURL", ctx, upd)}
+}
+
+func (_c *MockRollbacker_UpdateURL_Call) Run(run func(ctx context.Context, upd storage.Update)) *MockRollbacker_UpdateURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 storage.Update
+		if args[1] != nil {
+			arg1 = args[1].(storage.Update)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRollbacker_UpdateURL_Call) Return(change storage.Change, err error) *MockRollbacker_UpdateURL_Call {
+	_c.Call.Return(change, err)
+	return _c
+}
+
+func (_c *MockRollbacker_UpdateURL_Call) RunAndReturn(run func(ctx context.Context, upd storage.Update) (storage.Change, error)) *MockRollbacker_UpdateURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Version provides a mock function for the type MockRollbacker
+func (_mock *MockRollbacker) Version(ctx context.Context, alias string, version int64) (storage.Link, error) {
+	ret := _mock.Called(ctx, alias, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Version")
+	}
+
+	var r0 storage.Link
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) (storage.Link, error)); ok {
+		return returnFunc(ctx, alias, version)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) storage.Link); ok {
+		r0 = returnFunc(ctx, alias, version)
+	} else {
+		r0 = ret.Get(0).(storage.Link)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = returnFunc(ctx, alias, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRollbacker_Version_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Version'
+type MockRollbacker_Version_Call struct {
+	*mock.Call
+}
+
+// Version is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+//   - version int64
+func (_e *MockRollbacker_Expecter) Version(ctx interface{}, alias interface{}, version interface{}) *MockRollbacker_Version_Call {
+	return &MockRollbacker_Version_Call{Call: _e.mock.On("Version", ctx, alias, version)}
+}
+
+func (_c *MockRollbacker_Version_Call) Run(run func(ctx context.Context, alias string, version int64)) *MockRollbacker_Version_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRollbacker_Version_Call) Return(link storage.Link, err error) *MockRollbacker_Version_Call {
+	_c.Call.Return(link, err)
+	return _c
+}
+
+func (_c *MockRollbacker_Version_Call) RunAndReturn(run func(ctx context.Context, alias string, version int64) (storage.Link, error)) *MockRollbacker_Version_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,115 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+type Response struct {
+	response.Message
+	Links      []storage.Link `json:"links"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type UrlLister interface {
+	ListURLs(ctx context.Context, q storage.ListQuery) (storage.ListPage, error)
+}
+
+func New(log *slog.Logger, urlLister UrlLister) http.HandlerFunc {
+	return newList(log, urlLister, "http-server.handlers.url.list.New", false)
+}
+
+// NewTrash lists deleted links that have not been purged yet.
+func NewTrash(log *slog.Logger, urlLister UrlLister) http.HandlerFunc {
+	return newList(log, urlLister, "http-server.handlers.url.list.NewTrash", true)
+}
+
+func newList(log *slog.Logger, urlLister UrlLister, op string, trashed bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), op)
+
+		q, err := parseQuery(r)
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error(err.Error()),
+				"invalid list query", sl.Err(err))
+
+			return
+		}
+		q.Trashed = trashed
+
+		page, err := urlLister.ListURLs(r.Context(), q)
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to list URLs", sl.Err(err))
+
+			return
+		}
+
+		resp := Response{
+			Message: response.OK(),
+			Links:   page.Links,
+		}
+		if page.Next != nil {
+			resp.NextCursor = page.Next.Encode()
+		}
+
+		sl.WriteResponse(log, w, r, 0, resp,
+			"URLs listed", slog.Int("count", len(page.Links)))
+	}
+}
+
+var (
+	errInvalidLimit  = errors.New("parameter limit must be between 1 and " + strconv.Itoa(maxLimit))
+	errInvalidOrder  = errors.New("parameter order must be asc or desc")
+	errInvalidCursor = errors.New("parameter cursor is not valid")
+)
+
+func parseQuery(r *http.Request) (storage.ListQuery, error) {
+	params := r.URL.Query()
+
+	q := storage.ListQuery{
+		Limit:  defaultLimit,
+		Order:  storage.OrderDesc,
+		Search: params.Get("q"),
+		Host:   params.Get("host"),
+	}
+
+	if v := params.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return storage.ListQuery{}, errInvalidLimit
+		}
+		q.Limit = limit
+	}
+
+	if v := params.Get("order"); v != "" {
+		q.Order = storage.SortOrder(v)
+		if q.Order != storage.OrderAsc && q.Order != storage.OrderDesc {
+			return storage.ListQuery{}, errInvalidOrder
+		}
+	}
+
+	if v := params.Get("cursor"); v != "" {
+		cursor, err := storage.DecodeCursor(v)
+		if err != nil {
+			return storage.ListQuery{}, errInvalidCursor
+		}
+		q.After = &cursor
+	}
+
+	return q, nil
+}
@@ -0,0 +1,132 @@
+package list_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/list"
+	mocks "github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/list/mocks"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+func TestListHandler(t *testing.T) {
+	createdAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+	cursor := storage.Cursor{CreatedAt: createdAt, ID: 42}
+
+	cases := []struct {
+		name         string
+		query        string
+		trash        bool
+		expectedCode int
+		respError    string
+		mockQuery    storage.ListQuery
+		mockPage     storage.ListPage
+		mockError    error
+		nextCursor   string
+	}{
+		{
+			name:         "Defaults",
+			expectedCode: http.StatusOK,
+			mockQuery:    storage.ListQuery{Limit: 50, Order: storage.OrderDesc},
+			mockPage: storage.ListPage{
+				Links: []storage.Link{{Alias: "GoDuck", URL: "https://duckduckgo.com", CreatedAt: createdAt}},
+			},
+		},
+		{
+			name:         "Filters and cursor",
+			query:        "?limit=1&order=asc&q=duck&host=duckduckgo.com&cursor=" + cursor.Encode(),
+			expectedCode: http.StatusOK,
+			mockQuery: storage.ListQuery{
+				Limit:  1,
+				Order:  storage.OrderAsc,
+				Search: "duck",
+				Host:   "duckduckgo.com",
+				After:  &cursor,
+			},
+			mockPage: storage.ListPage{
+				Links: []storage.Link{{Alias: "GoDuck", URL: "https://duckduckgo.com", CreatedAt: createdAt}},
+				Next:  &storage.Cursor{CreatedAt: createdAt, ID: 43},
+			},
+			nextCursor: storage.Cursor{CreatedAt: createdAt, ID: 43}.Encode(),
+		},
+		{
+			name:         "Trash",
+			query:        "?limit=10",
+			trash:        true,
+			expectedCode: http.StatusOK,
+			mockQuery:    storage.ListQuery{Limit: 10, Order: storage.OrderDesc, Trashed: true},
+			mockPage: storage.ListPage{
+				Links: []storage.Link{{Alias: "GoDuck", URL: "https://duckduckgo.com", CreatedAt: createdAt, DeletedAt: &createdAt}},
+			},
+		},
+		{
+			name:         "Invalid limit",
+			query:        "?limit=0",
+			expectedCode: http.StatusBadRequest,
+			respError:    "parameter limit must be between 1 and 500",
+		},
+		{
+			name:         "Invalid order",
+			query:        "?order=random",
+			expectedCode: http.StatusBadRequest,
+			respError:    "parameter order must be asc or desc",
+		},
+		{
+			name:         "Invalid cursor",
+			query:        "?cursor=not-a-cursor",
+			expectedCode: http.StatusBadRequest,
+			respError:    "parameter cursor is not valid",
+		},
+		{
+			name:         "ListURLs Error",
+			expectedCode: http.StatusInternalServerError,
+			respError:    "internal error",
+			mockQuery:    storage.ListQuery{Limit: 50, Order: storage.OrderDesc},
+			mockError:    errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlListerMock := mocks.NewMockUrlLister(t)
+
+			if tc.expectedCode != http.StatusBadRequest {
+				urlListerMock.On("ListURLs", mock.Anything, tc.mockQuery).
+					Return(tc.mockPage, tc.mockError).Once()
+			}
+
+			handler := list.New(sldiscard.NewDiscardLogger(), urlListerMock)
+			if tc.trash {
+				handler = list.NewTrash(sldiscard.NewDiscardLogger(), urlListerMock)
+			}
+
+			req, err := http.NewRequest(http.MethodGet, "/url"+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+
+			var resp list.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+
+			if tc.expectedCode == http.StatusOK {
+				require.Len(t, resp.Links, len(tc.mockPage.Links))
+				require.Equal(t, tc.nextCursor, resp.NextCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package list_mocks
+
+import (
+	"context"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockUrlLister creates a new instance of MockUrlLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUrlLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUrlLister {
+	mock := &MockUrlLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUrlLister is an autogenerated mock type for the UrlLister type
+type MockUrlLister struct {
+	mock.Mock
+}
+
+type MockUrlLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUrlLister) EXPECT() *MockUrlLister_Expecter {
+	return &MockUrlLister_Expecter{mock: &_m.Mock}
+}
+
+// ListURLs provides a mock function for the type MockUrlLister
+func (_mock *MockUrlLister) ListURLs(ctx context.Context, q storage.ListQuery) (storage.ListPage, error) {
+	ret := _mock.Called(ctx, q)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListURLs")
+	}
+
+	var r0 storage.ListPage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, storage.ListQuery) (storage.ListPage, error)); ok {
+		return returnFunc(ctx, q)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, storage.ListQuery) storage.ListPage); ok {
+		r0 = returnFunc(ctx, q)
+	} else {
+		r0 = ret.Get(0).(storage.ListPage)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, storage.ListQuery) error); ok {
+		r1 = returnFunc(ctx, q)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUrlLister_ListURLs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListURLs'
+type MockUrlLister_ListURLs_Call struct {
+	*mock.Call
+}
+
+// ListURLs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - q storage.ListQuery
+func (_e *MockUrlLister_Expecter) ListURLs(ctx interface{}, q interface{}) *MockUrlLister_ListURLs_Call {
+	return &MockUrlLister_ListURLs_Call{Call: _e.mock.On("ListURLs", ctx, q)}
+}
+
+func (_c *MockUrlLister_ListURLs_Call) Run(run func(ctx context.Context, q storage.ListQuery)) *MockUrlLister_ListURLs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 storage.ListQuery
+		if args[1] != nil {
+			arg1 = args[1].(storage.ListQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUrlLister_ListURLs_Call) Return(listPage storage.ListPage, err error) *MockUrlLister_ListURLs_Call {
+	_c.Call.Return(listPage, err)
+	return _c
+}
+
+func (_c *MockUrlLister_ListURLs_Call) RunAndReturn(run func(ctx context.Context, q storage.ListQuery) (storage.ListPage, error)) *MockUrlLister_ListURLs_Call {
+	_c.Call.Return(run)
+	return _c
+}
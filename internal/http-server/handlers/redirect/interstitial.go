@@ -0,0 +1,36 @@
+package redirect
+
+import (
+	"html/template"
+	"net/http"
+)
+
+var interstitial = template.Must(template.New("interstitial").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Warning: unsafe link</title>
+</head>
+<body>
+<h1>This link has been disabled</h1>
+<p>The destination of this short link is on our list of phishing and malware sites ({{.Reason}}), so we are not redirecting you there.</p>
+<p>Destination: <code>{{.URL}}</code></p>
+</body>
+</html>
+`))
+
+type interstitialData struct {
+	URL    string
+	Reason string
+}
+
+// writeInterstitial serves the warning page shown instead of a redirect
+// to a blocklisted destination. The destination is shown as text only.
+func writeInterstitial(w http.ResponseWriter, data interstitialData) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusForbidden)
+
+	return interstitial.Execute(w, data)
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package redirect_mocks
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockBlocklist creates a new instance of MockBlocklist. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockBlocklist(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockBlocklist {
+	mock := &MockBlocklist{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockBlocklist is an autogenerated mock type for the Blocklist type
+type MockBlocklist struct {
+	mock.Mock
+}
+
+type MockBlocklist_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockBlocklist) EXPECT() *MockBlocklist_Expecter {
+	return &MockBlocklist_Expecter{mock: &_m.Mock}
+}
+
+// Check provides a mock function for the type MockBlocklist
+func (_mock *MockBlocklist) Check(ctx context.Context, raw string) error {
+	ret := _mock.Called(ctx, raw)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, raw)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockBlocklist_Check_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Check'
+type MockBlocklist_Check_Call struct {
+	*mock.Call
+}
+
+// Check is a helper method to define mock.On call
+//   - ctx context.Context
+//   - raw string
+func (_e *MockBlocklist_Expecter) Check(ctx interface{}, raw interface{}) *MockBlocklist_Check_Call {
+	return &MockBlocklist_Check_Call{Call: _e.mock.On("Check", ctx, raw)}
+}
+
+func (_c *MockBlocklist_Check_Call) Run(run func(ctx context.Context, raw string)) *MockBlocklist_Check_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockBlocklist_Check_Call) Return(err error) *MockBlocklist_Check_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockBlocklist_Check_Call) RunAndReturn(run func(ctx context.Context, raw string) error) *MockBlocklist_Check_Call {
+	_c.Call.Return(run)
+	return _c
+}
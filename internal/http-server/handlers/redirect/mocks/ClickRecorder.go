@@ -0,0 +1,84 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package redirect_mocks
+
+import (
+	"net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockClickRecorder creates a new instance of MockClickRecorder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockClickRecorder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockClickRecorder {
+	mock := &MockClickRecorder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockClickRecorder is an autogenerated mock type for the ClickRecorder type
+type MockClickRecorder struct {
+	mock.Mock
+}
+
+type MockClickRecorder_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockClickRecorder) EXPECT() *MockClickRecorder_Expecter {
+	return &MockClickRecorder_Expecter{mock: &_m.Mock}
+}
+
+// Record provides a mock function for the type MockClickRecorder
+func (_mock *MockClickRecorder) Record(r *http.Request, alias string) {
+	_mock.Called(r, alias)
+	return
+}
+
+// MockClickRecorder_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type MockClickRecorder_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - r *http.Request
+//   - alias string
+func (_e *MockClickRecorder_Expecter) Record(r interface{}, alias interface{}) *MockClickRecorder_Record_Call {
+	return &MockClickRecorder_Record_Call{Call: _e.mock.On("Record", r, alias)}
+}
+
+func (_c *MockClickRecorder_Record_Call) Run(run func(r *http.Request, alias string)) *MockClickRecorder_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *http.Request
+		if args[0] != nil {
+			arg0 = args[0].(*http.Request)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClickRecorder_Record_Call) Return() *MockClickRecorder_Record_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockClickRecorder_Record_Call) RunAndReturn(run func(r *http.Request, alias string)) *MockClickRecorder_Record_Call {
+	_c.Run(run)
+	return _c
+}
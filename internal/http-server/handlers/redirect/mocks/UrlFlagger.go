@@ -0,0 +1,101 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package redirect_mocks
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockUrlFlagger creates a new instance of MockUrlFlagger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUrlFlagger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUrlFlagger {
+	mock := &MockUrlFlagger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUrlFlagger is an autogenerated mock type for the UrlFlagger type
+type MockUrlFlagger struct {
+	mock.Mock
+}
+
+type MockUrlFlagger_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUrlFlagger) EXPECT() *MockUrlFlagger_Expecter {
+	return &MockUrlFlagger_Expecter{mock: &_m.Mock}
+}
+
+// FlagURL provides a mock function for the type MockUrlFlagger
+func (_mock *MockUrlFlagger) FlagURL(ctx context.Context, alias string, reason string) error {
+	ret := _mock.Called(ctx, alias, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FlagURL")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, alias, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUrlFlagger_FlagURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FlagURL'
+type MockUrlFlagger_FlagURL_Call struct {
+	*mock.Call
+}
+
+// FlagURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+//   - reason string
+func (_e *MockUrlFlagger_Expecter) FlagURL(ctx interface{}, alias interface{}, reason interface{}) *MockUrlFlagger_FlagURL_Call {
+	return &MockUrlFlagger_FlagURL_Call{Call: _e.mock.On("FlagURL", ctx, alias, reason)}
+}
+
+func (_c *MockUrlFlagger_FlagURL_Call) Run(run func(ctx context.Context, alias string, reason string)) *MockUrlFlagger_FlagURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUrlFlagger_FlagURL_Call) Return(err error) *MockUrlFlagger_FlagURL_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUrlFlagger_FlagURL_Call) RunAndReturn(run func(ctx context.Context, alias string, reason string) error) *MockUrlFlagger_FlagURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
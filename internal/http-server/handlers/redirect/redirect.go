@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"sync"
 
 	"github.com/go-chi/chi/v5"
 
@@ -17,7 +18,24 @@ type UrlGetter interface {
 	GetURL(ctx context.Context, alias string) (string, error)
 }
 
-func New(log *slog.Logger, urlGetter UrlGetter) http.HandlerFunc {
+type ClickRecorder interface {
+	Record(r *http.Request, alias string)
+}
+
+type Blocklist interface {
+	Check(ctx context.Context, raw string) error
+}
+
+type UrlFlagger interface {
+	FlagURL(ctx context.Context, alias, reason string) error
+}
+
+// New returns the redirect handler. Destinations found on the blocklist
+// are flagged in storage and get a warning page instead of a redirect.
+// Each alias is flagged once per destination, not on every hit.
+func New(log *slog.Logger, urlGetter UrlGetter, recorder ClickRecorder, blocklist Blocklist, flagger UrlFlagger) http.HandlerFunc {
+	var flagged sync.Map
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		log := sl.Init(log, r.Context(), "http-server.handlers.url.redirect.New")
 
@@ -38,6 +56,20 @@ func New(log *slog.Logger, urlGetter UrlGetter) http.HandlerFunc {
 
 			return
 		}
+		if errors.Is(err, storage.ErrUrlExpired) {
+			sl.WriteResponse(log, w, r, http.StatusGone,
+				response.Error("URL expired"),
+				"URL expired", slog.String("alias", alias))
+
+			return
+		}
+		if errors.Is(err, storage.ErrUrlDeleted) {
+			sl.WriteResponse(log, w, r, http.StatusGone,
+				response.Error("URL deleted"),
+				"URL deleted", slog.String("alias", alias))
+
+			return
+		}
 		if err != nil {
 			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
 				response.Error("internal error"),
@@ -47,6 +79,30 @@ func New(log *slog.Logger, urlGetter UrlGetter) http.HandlerFunc {
 		}
 
 		log.Info("got URL", slog.String("url", resURL))
+
+		if blocklist != nil {
+			if listed := blocklist.Check(r.Context(), resURL); listed != nil {
+				log.Warn("destination is blocklisted", slog.String("alias", alias), slog.String("url", resURL), sl.Err(listed))
+
+				key := alias + "\x00" + resURL
+				if _, seen := flagged.LoadOrStore(key, struct{}{}); flagger != nil && !seen {
+					if err := flagger.FlagURL(r.Context(), alias, listed.Error()); err != nil {
+						flagged.Delete(key)
+						log.Error("failed to flag URL", slog.String("alias", alias), sl.Err(err))
+					}
+				}
+
+				if err := writeInterstitial(w, interstitialData{URL: resURL, Reason: listed.Error()}); err != nil {
+					log.Error("failed to write interstitial", sl.Err(err))
+				}
+
+				return
+			}
+		}
+
+		if recorder != nil {
+			recorder.Record(r, alias)
+		}
 		http.Redirect(w, r, resURL, http.StatusFound)
 	}
 }
@@ -23,6 +23,8 @@ func TestRedirectHandler(t *testing.T) {
 		expectedCode int
 		mockUrl      string
 		mockError    error
+		listed       error
+		flagError    error
 	}{
 		{
 			name:         "Success",
@@ -30,6 +32,21 @@ func TestRedirectHandler(t *testing.T) {
 			expectedCode: http.StatusFound,
 			mockUrl:      "https://duckduckgo.com",
 		},
+		{
+			name:         "Blocklisted",
+			alias:        "GoPhish",
+			expectedCode: http.StatusForbidden,
+			mockUrl:      "https://phish.example/login",
+			listed:       errors.New("destination domain is on the blocklist"),
+		},
+		{
+			name:         "Blocklisted, flag fails",
+			alias:        "GoPhish",
+			expectedCode: http.StatusForbidden,
+			mockUrl:      "https://phish.example/login",
+			listed:       errors.New("destination domain is on the blocklist"),
+			flagError:    errors.New("db is down"),
+		},
 		{
 			name:         "Empty alias",
 			alias:        "",
@@ -41,6 +58,18 @@ func TestRedirectHandler(t *testing.T) {
 			expectedCode: http.StatusNotFound,
 			mockError:    storage.ErrUrlNotFound,
 		},
+		{
+			name:         "URL expired",
+			alias:        "some_alias",
+			expectedCode: http.StatusGone,
+			mockError:    storage.ErrUrlExpired,
+		},
+		{
+			name:         "URL deleted",
+			alias:        "some_alias",
+			expectedCode: http.StatusGone,
+			mockError:    storage.ErrUrlDeleted,
+		},
 		{
 			name:         "GetURL Error",
 			alias:        "some_alias",
@@ -55,12 +84,15 @@ func TestRedirectHandler(t *testing.T) {
 			t.Parallel()
 
 			urlGetterMock := mocks.NewMockUrlGetter(t)
+			clickRecorderMock := mocks.NewMockClickRecorder(t)
+			blocklistMock := mocks.NewMockBlocklist(t)
+			urlFlaggerMock := mocks.NewMockUrlFlagger(t)
 
 			if tc.expectedCode == http.StatusBadRequest {
 				req, _ := http.NewRequest(http.MethodGet, "/", nil)
 				rr := httptest.NewRecorder()
 
-				handler := redirect.New(sldiscard.NewDiscardLogger(), urlGetterMock)
+				handler := redirect.New(sldiscard.NewDiscardLogger(), urlGetterMock, clickRecorderMock, blocklistMock, urlFlaggerMock)
 				handler.ServeHTTP(rr, req)
 
 				require.Equal(t, tc.expectedCode, rr.Code)
@@ -69,9 +101,20 @@ func TestRedirectHandler(t *testing.T) {
 
 			urlGetterMock.On("GetURL", mock.Anything, tc.alias).
 				Return(tc.mockUrl, tc.mockError).Once()
+			if tc.mockError == nil {
+				blocklistMock.On("Check", mock.Anything, tc.mockUrl).
+					Return(tc.listed).Once()
+			}
+			if tc.listed != nil {
+				urlFlaggerMock.On("FlagURL", mock.Anything, tc.alias, tc.listed.Error()).
+					Return(tc.flagError).Once()
+			}
+			if tc.expectedCode == http.StatusFound {
+				clickRecorderMock.On("Record", mock.Anything, tc.alias).Once()
+			}
 
 			router := chi.NewRouter()
-			router.Get("/url/{alias}", redirect.New(sldiscard.NewDiscardLogger(), urlGetterMock))
+			router.Get("/url/{alias}", redirect.New(sldiscard.NewDiscardLogger(), urlGetterMock, clickRecorderMock, blocklistMock, urlFlaggerMock))
 
 			req, err := http.NewRequest(http.MethodGet, "/url/"+tc.alias, nil)
 			require.NoError(t, err)
@@ -83,6 +126,38 @@ func TestRedirectHandler(t *testing.T) {
 			if tc.expectedCode == http.StatusFound {
 				require.Equal(t, tc.mockUrl, rr.Header().Get("Location"))
 			}
+			if tc.listed != nil {
+				require.Empty(t, rr.Header().Get("Location"))
+				require.Contains(t, rr.Header().Get("Content-Type"), "text/html")
+				require.Contains(t, rr.Body.String(), tc.mockUrl)
+				require.Contains(t, rr.Body.String(), tc.listed.Error())
+			}
 		})
 	}
 }
+
+func TestRedirectHandler_FlagsOnce(t *testing.T) {
+	listed := errors.New("destination domain is on the blocklist")
+
+	urlGetterMock := mocks.NewMockUrlGetter(t)
+	blocklistMock := mocks.NewMockBlocklist(t)
+	urlFlaggerMock := mocks.NewMockUrlFlagger(t)
+
+	urlGetterMock.On("GetURL", mock.Anything, "GoPhish").Return("https://phish.example/login", nil).Times(4)
+	blocklistMock.On("Check", mock.Anything, "https://phish.example/login").Return(listed).Times(4)
+	// A failed flag is retried on the next hit, a stored one is not.
+	urlFlaggerMock.On("FlagURL", mock.Anything, "GoPhish", listed.Error()).Return(errors.New("db is down")).Once()
+	urlFlaggerMock.On("FlagURL", mock.Anything, "GoPhish", listed.Error()).Return(nil).Once()
+
+	router := chi.NewRouter()
+	router.Get("/url/{alias}", redirect.New(sldiscard.NewDiscardLogger(), urlGetterMock, mocks.NewMockClickRecorder(t), blocklistMock, urlFlaggerMock))
+
+	for i := 0; i < 4; i++ {
+		req, err := http.NewRequest(http.MethodGet, "/url/GoPhish", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	}
+}
@@ -0,0 +1,260 @@
+package save
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/alias"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+// MaxBatchItems is the largest number of items accepted in one batch.
+const MaxBatchItems = 10000
+
+// ndjson is the content type of a newline-delimited stream of requests.
+const ndjson = "application/x-ndjson"
+
+type BatchResult struct {
+	Index     int        `json:"index"`
+	Status    int        `json:"status"`
+	URL       string     `json:"url,omitempty"`
+	Alias     string     `json:"alias,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Reused    bool       `json:"reused,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	response.Message
+	Saved   int           `json:"saved"`
+	Failed  int           `json:"failed"`
+	Results []BatchResult `json:"results"`
+}
+
+type BatchSaver interface {
+	SaveURLs(ctx context.Context, links []storage.Link) []error
+	FindAlias(ctx context.Context, u string) (string, error)
+}
+
+// NewBatch returns the batch save handler. It accepts a JSON array of save
+// requests, or one request per line when sent as application/x-ndjson, and
+// reports the outcome of every item instead of failing the whole batch.
+func NewBatch(log *slog.Logger, saver BatchSaver, gen AliasGenerator, policy AliasPolicy, canonicalizer UrlCanonicalizer, destinations DestinationPolicy) http.HandlerFunc {
+	if gen == nil {
+		gen = &DefaultRandomAlias{}
+	}
+	if policy == nil {
+		policy = &alias.Policy{}
+	}
+
+	prep := newPreparer(policy, canonicalizer, destinations)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.url.save.NewBatch")
+
+		reqs, err := decodeBatch(r)
+		if errors.Is(err, io.EOF) {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("empty request"),
+				"request body is empty")
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("invalid request body"),
+				"failed to decode request body", sl.Err(err))
+
+			return
+		}
+		if len(reqs) > MaxBatchItems {
+			sl.WriteResponse(log, w, r, http.StatusRequestEntityTooLarge,
+				response.Error(fmt.Sprintf("batch must contain at most %d items", MaxBatchItems)),
+				"batch too large", slog.Int("items", len(reqs)))
+
+			return
+		}
+
+		log.Info("batch decoded successfully", slog.Int("items", len(reqs)))
+
+		results := make([]BatchResult, len(reqs))
+		links := make([]storage.Link, len(reqs))
+		var named, generated []int
+
+		now := time.Now()
+		for i, req := range reqs {
+			results[i].Index = i
+
+			link, rej := prep.prepare(r.Context(), req, now)
+			if rej != nil {
+				results[i].Status, results[i].Error = rej.status, rej.message
+				continue
+			}
+			links[i] = link
+
+			if req.Reuse {
+				existing, err := saver.FindAlias(r.Context(), link.URL)
+				if err == nil {
+					results[i] = BatchResult{Index: i, Status: http.StatusOK, URL: link.URL, Alias: existing, Reused: true}
+					continue
+				}
+				if !errors.Is(err, storage.ErrUrlNotFound) {
+					log.Error("failed to look up existing alias", slog.Int("index", i), sl.Err(err))
+					results[i].Status, results[i].Error = http.StatusInternalServerError, "internal server error"
+					continue
+				}
+			}
+
+			if link.Alias != "" {
+				named = append(named, i)
+			} else {
+				generated = append(generated, i)
+			}
+		}
+
+		for _, i := range saveBatch(r.Context(), saver, links, named, results) {
+			results[i].Status, results[i].Error = http.StatusConflict, "alias already exists"
+		}
+
+		for attempt := 0; attempt < 10 && len(generated) > 0; attempt++ {
+			pending := generated[:0]
+			for _, i := range generated {
+				links[i].Alias, err = generateAlias(r.Context(), gen, policy)
+				if err != nil {
+					log.Error("failed to generate alias", slog.Int("index", i), sl.Err(err))
+					results[i].Status, results[i].Error = http.StatusInternalServerError, "internal server error"
+					continue
+				}
+				pending = append(pending, i)
+			}
+
+			generated = saveBatch(r.Context(), saver, links, pending, results)
+			if len(generated) > 0 {
+				log.Warn("alias collisions, regenerating", slog.Int("count", len(generated)), slog.Int("attempt", attempt+1))
+			}
+		}
+		for _, i := range generated {
+			results[i].Status, results[i].Error = http.StatusInternalServerError, "alias collision after multiple attempts, try again later"
+		}
+
+		resp := BatchResponse{Message: response.OK(), Results: results}
+		for _, res := range results {
+			if res.Error == "" {
+				resp.Saved++
+			} else {
+				resp.Failed++
+			}
+		}
+
+		sl.WriteResponse(log, w, r, 0, resp, "batch processed",
+			slog.Int("saved", resp.Saved), slog.Int("failed", resp.Failed))
+	}
+}
+
+// decodeBatch reads the requests of a batch from a JSON array, or from a
+// stream of JSON objects when the body is NDJSON. Either way it stops after
+// MaxBatchItems+1 requests, so that oversized batches are refused without
+// reading them whole.
+func decodeBatch(r *http.Request) ([]Request, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	dec := json.NewDecoder(r.Body)
+
+	var reqs []Request
+	if mediaType != ndjson {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return nil, errors.New("batch must be a JSON array")
+		}
+
+		for dec.More() && len(reqs) <= MaxBatchItems {
+			var req Request
+			if err := dec.Decode(&req); err != nil {
+				return nil, err
+			}
+			reqs = append(reqs, req)
+		}
+		if len(reqs) <= MaxBatchItems {
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+		}
+		if len(reqs) == 0 {
+			return nil, io.EOF
+		}
+		return reqs, nil
+	}
+
+	for len(reqs) <= MaxBatchItems {
+		var req Request
+		err := dec.Decode(&req)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+	}
+	if len(reqs) == 0 {
+		return nil, io.EOF
+	}
+
+	return reqs, nil
+}
+
+// saveBatch stores the links at idx, fills in the results of those that
+// were saved or failed for good, and returns the indices whose alias was
+// already taken.
+func saveBatch(ctx context.Context, saver BatchSaver, links []storage.Link, idx []int, results []BatchResult) []int {
+	if len(idx) == 0 {
+		return nil
+	}
+
+	batch := make([]storage.Link, len(idx))
+	for j, i := range idx {
+		batch[j] = links[i]
+	}
+
+	var taken []int
+	for j, err := range saver.SaveURLs(ctx, batch) {
+		i := idx[j]
+		switch {
+		case err == nil:
+			link := links[i]
+			results[i] = BatchResult{Index: i, Status: http.StatusOK, URL: link.URL, Alias: link.Alias, ExpiresAt: link.ExpiresAt}
+		case errors.Is(err, storage.ErrUrlExists):
+			taken = append(taken, i)
+		default:
+			results[i].Status, results[i].Error = http.StatusInternalServerError, "internal server error"
+		}
+	}
+
+	return taken
+}
+
+// generateAlias draws aliases from gen until one passes the policy.
+func generateAlias(ctx context.Context, gen AliasGenerator, policy AliasPolicy) (string, error) {
+	for attempt := 0; attempt < 10; attempt++ {
+		candidate, err := gen.Generate(ctx)
+		if err != nil {
+			return "", err
+		}
+		if policy.Check(candidate) == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", errors.New("no generated alias passed the alias policy")
+}
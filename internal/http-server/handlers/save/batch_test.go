@@ -0,0 +1,188 @@
+package save_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/save"
+	mocks "github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/save/mocks"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+func TestBatchHandler(t *testing.T) {
+	batchSaverMock := mocks.NewMockBatchSaver(t)
+	batchSaverMock.On("SaveURLs", mock.Anything, mock.MatchedBy(func(links []storage.Link) bool {
+		return len(links) == 2 && links[0].Alias == "GoDuck" && links[1].Alias == "taken"
+	})).Return([]error{nil, storage.ErrUrlExists}).Once()
+	batchSaverMock.On("FindAlias", mock.Anything, urlStr).Return("existing", nil).Once()
+
+	handler := save.NewBatch(sldiscard.NewDiscardLogger(), batchSaverMock, nil, nil, nil, nil)
+
+	input := `[
+		{"url": "https://duckduckgo.com", "alias": "GoDuck"},
+		{"url": "ht!tp://invalid-url"},
+		{"url": "https://duckduckgo.com", "alias": "taken"},
+		{"url": "https://duckduckgo.com", "reuse": true},
+		{"url": "https://duckduckgo.com", "ttl": "soon"}
+	]`
+	req, err := http.NewRequest(http.MethodPost, "/batch", strings.NewReader(input))
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var resp save.BatchResponse
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, 2, resp.Saved)
+	require.Equal(t, 3, resp.Failed)
+	require.Len(t, resp.Results, 5)
+
+	require.Equal(t, http.StatusOK, resp.Results[0].Status)
+	require.Equal(t, "GoDuck", resp.Results[0].Alias)
+	require.Equal(t, http.StatusBadRequest, resp.Results[1].Status)
+	require.Equal(t, "field URL is not a valid URL", resp.Results[1].Error)
+	require.Equal(t, http.StatusConflict, resp.Results[2].Status)
+	require.Equal(t, "alias already exists", resp.Results[2].Error)
+	require.Equal(t, http.StatusOK, resp.Results[3].Status)
+	require.Equal(t, "existing", resp.Results[3].Alias)
+	require.True(t, resp.Results[3].Reused)
+	require.Equal(t, http.StatusBadRequest, resp.Results[4].Status)
+	require.Equal(t, "field TTL is not a valid duration", resp.Results[4].Error)
+
+	for i, res := range resp.Results {
+		require.Equal(t, i, res.Index)
+	}
+}
+
+func TestBatchHandler_NDJSONRegeneratesCollisions(t *testing.T) {
+	aliasGenMock := mocks.NewMockAliasGenerator(t)
+	aliasGenMock.On("Generate", mock.Anything).Return("first", nil).Once()
+	aliasGenMock.On("Generate", mock.Anything).Return("second", nil).Once()
+	aliasGenMock.On("Generate", mock.Anything).Return("third", nil).Once()
+
+	batchSaverMock := mocks.NewMockBatchSaver(t)
+	batchSaverMock.On("SaveURLs", mock.Anything, mock.MatchedBy(func(links []storage.Link) bool {
+		return len(links) == 2 && links[0].Alias == "first" && links[1].Alias == "second"
+	})).Return([]error{nil, storage.ErrUrlExists}).Once()
+	batchSaverMock.On("SaveURLs", mock.Anything, mock.MatchedBy(func(links []storage.Link) bool {
+		return len(links) == 1 && links[0].Alias == "third"
+	})).Return([]error{nil}).Once()
+
+	handler := save.NewBatch(sldiscard.NewDiscardLogger(), batchSaverMock, aliasGenMock, nil, nil, nil)
+
+	input := "{\"url\": \"https://duckduckgo.com\"}\n{\"url\": \"https://example.com\"}\n"
+	req, err := http.NewRequest(http.MethodPost, "/batch", strings.NewReader(input))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var resp save.BatchResponse
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, 2, resp.Saved)
+	require.Equal(t, "first", resp.Results[0].Alias)
+	require.Equal(t, "third", resp.Results[1].Alias)
+	require.Equal(t, "https://example.com", resp.Results[1].URL)
+}
+
+func TestBatchHandler_StorageFailure(t *testing.T) {
+	batchSaverMock := mocks.NewMockBatchSaver(t)
+	batchSaverMock.On("SaveURLs", mock.Anything, mock.Anything).
+		Return([]error{errors.New("connection reset"), errors.New("connection reset")}).Once()
+
+	handler := save.NewBatch(sldiscard.NewDiscardLogger(), batchSaverMock, nil, nil, nil, nil)
+
+	input := `[{"url": "https://duckduckgo.com", "alias": "one"}, {"url": "https://duckduckgo.com", "alias": "two"}]`
+	req, err := http.NewRequest(http.MethodPost, "/batch", strings.NewReader(input))
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var resp save.BatchResponse
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, 0, resp.Saved)
+	require.Equal(t, 2, resp.Failed)
+	for _, res := range resp.Results {
+		require.Equal(t, http.StatusInternalServerError, res.Status)
+		require.Equal(t, "internal server error", res.Error)
+	}
+}
+
+func TestBatchHandler_InvalidBody(t *testing.T) {
+	cases := []struct {
+		name        string
+		body        string
+		contentType string
+		respError   string
+	}{
+		{name: "Empty body", body: "", respError: "empty request"},
+		{name: "Empty array", body: "[]", respError: "empty request"},
+		{name: "Not an array", body: `{"url": "https://duckduckgo.com"}`, respError: "invalid request body"},
+		{name: "Unterminated array", body: `[{"url": "https://duckduckgo.com"}`, respError: "invalid request body"},
+		{name: "Broken NDJSON", body: "{\"url\": \"https://duckduckgo.com\"}\n{oops\n", contentType: "application/x-ndjson", respError: "invalid request body"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := save.NewBatch(sldiscard.NewDiscardLogger(), mocks.NewMockBatchSaver(t), nil, nil, nil, nil)
+
+			req, err := http.NewRequest(http.MethodPost, "/batch", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			var resp save.BatchResponse
+			require.Equal(t, http.StatusBadRequest, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
+
+// failingReader stands in for the rest of a body that must not be read.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read past the batch limit")
+}
+
+func TestBatchHandler_TooLarge(t *testing.T) {
+	item := `{"url": "https://duckduckgo.com"},`
+	body := io.MultiReader(strings.NewReader("["+strings.Repeat(item, save.MaxBatchItems+1)), failingReader{})
+
+	handler := save.NewBatch(sldiscard.NewDiscardLogger(), mocks.NewMockBatchSaver(t), nil, nil, nil, nil)
+
+	req, err := http.NewRequest(http.MethodPost, "/batch", body)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var resp save.BatchResponse
+	require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, fmt.Sprintf("batch must contain at most %d items", save.MaxBatchItems), resp.Error)
+}
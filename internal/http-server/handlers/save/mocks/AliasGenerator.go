@@ -5,6 +5,8 @@
 package save_mocks
 
 import (
+	"context"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -36,20 +38,29 @@ func (_m *MockAliasGenerator) EXPECT() *MockAliasGenerator_Expecter {
 }
 
 // Generate provides a mock function for the type MockAliasGenerator
-func (_mock *MockAliasGenerator) Generate() string {
-	ret := _mock.Called()
+func (_mock *MockAliasGenerator) Generate(ctx context.Context) (string, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Generate")
 	}
 
 	var r0 string
-	if returnFunc, ok := ret.Get(0).(func() string); ok {
-		r0 = returnFunc()
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
 // MockAliasGenerator_Generate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Generate'
@@ -58,23 +69,30 @@ type MockAliasGenerator_Generate_Call struct {
 }
 
 // Generate is a helper method to define mock.On call
-func (_e *MockAliasGenerator_Expecter) Generate() *MockAliasGenerator_Generate_Call {
-	return &MockAliasGenerator_Generate_Call{Call: _e.mock.On("Generate")}
+//   - ctx context.Context
+func (_e *MockAliasGenerator_Expecter) Generate(ctx interface{}) *MockAliasGenerator_Generate_Call {
+	return &MockAliasGenerator_Generate_Call{Call: _e.mock.On("Generate", ctx)}
 }
 
-func (_c *MockAliasGenerator_Generate_Call) Run(run func()) *MockAliasGenerator_Generate_Call {
+func (_c *MockAliasGenerator_Generate_Call) Run(run func(ctx context.Context)) *MockAliasGenerator_Generate_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
 
-func (_c *MockAliasGenerator_Generate_Call) Return(s string) *MockAliasGenerator_Generate_Call {
-	_c.Call.Return(s)
+func (_c *MockAliasGenerator_Generate_Call) Return(s string, err error) *MockAliasGenerator_Generate_Call {
+	_c.Call.Return(s, err)
 	return _c
 }
 
-func (_c *MockAliasGenerator_Generate_Call) RunAndReturn(run func() string) *MockAliasGenerator_Generate_Call {
+func (_c *MockAliasGenerator_Generate_Call) RunAndReturn(run func(ctx context.Context) (string, error)) *MockAliasGenerator_Generate_Call {
 	_c.Call.Return(run)
 	return _c
 }
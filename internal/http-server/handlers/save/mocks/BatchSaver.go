@@ -0,0 +1,164 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package save_mocks
+
+import (
+	"context"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockBatchSaver creates a new instance of MockBatchSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockBatchSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockBatchSaver {
+	mock := &MockBatchSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockBatchSaver is an autogenerated mock type for the BatchSaver type
+type MockBatchSaver struct {
+	mock.Mock
+}
+
+type MockBatchSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockBatchSaver) EXPECT() *MockBatchSaver_Expecter {
+	return &MockBatchSaver_Expecter{mock: &_m.Mock}
+}
+
+// FindAlias provides a mock function for the type MockBatchSaver
+func (_mock *MockBatchSaver) FindAlias(ctx context.Context, u string) (string, error) {
+	ret := _mock.Called(ctx, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAlias")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, u)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, u)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, u)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockBatchSaver_FindAlias_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAlias'
+type MockBatchSaver_FindAlias_Call struct {
+	*mock.Call
+}
+
+// FindAlias is a helper method to define mock.On call
+//   - ctx context.Context
+//   - u string
+func (_e *MockBatchSaver_Expecter) FindAlias(ctx interface{}, u interface{}) *MockBatchSaver_FindAlias_Call {
+	return &MockBatchSaver_FindAlias_Call{Call: _e.mock.On("FindAlias", ctx, u)}
+}
+
+func (_c *MockBatchSaver_FindAlias_Call) Run(run func(ctx context.Context, u string)) *MockBatchSaver_FindAlias_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockBatchSaver_FindAlias_Call) Return(s string, err error) *MockBatchSaver_FindAlias_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockBatchSaver_FindAlias_Call) RunAndReturn(run func(ctx context.Context, u string) (string, error)) *MockBatchSaver_FindAlias_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveURLs provides a mock function for the type MockBatchSaver
+func (_mock *MockBatchSaver) SaveURLs(ctx context.Context, links []storage.Link) []error {
+	ret := _mock.Called(ctx, links)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveURLs")
+	}
+
+	var r0 []error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []storage.Link) []error); ok {
+		r0 = returnFunc(ctx, links)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]error)
+		}
+	}
+	return r0
+}
+
+// MockBatchSaver_SaveURLs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveURLs'
+type MockBatchSaver_SaveURLs_Call struct {
+	*mock.Call
+}
+
+// SaveURLs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - links []storage.Link
+func (_e *MockBatchSaver_Expecter) SaveURLs(ctx interface{}, links interface{}) *MockBatchSaver_SaveURLs_Call {
+	return &MockBatchSaver_SaveURLs_Call{Call: _e.mock.On("SaveURLs", ctx, links)}
+}
+
+func (_c *MockBatchSaver_SaveURLs_Call) Run(run func(ctx context.Context, links []storage.Link)) *MockBatchSaver_SaveURLs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []storage.Link
+		if args[1] != nil {
+			arg1 = args[1].([]storage.Link)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockBatchSaver_SaveURLs_Call) Return(errs []error) *MockBatchSaver_SaveURLs_Call {
+	_c.Call.Return(errs)
+	return _c
+}
+
+func (_c *MockBatchSaver_SaveURLs_Call) RunAndReturn(run func(ctx context.Context, links []storage.Link) []error) *MockBatchSaver_SaveURLs_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -7,6 +7,7 @@ package save_mocks
 import (
 	"context"
 
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -37,17 +38,83 @@ func (_m *MockUrlSaver) EXPECT() *MockUrlSaver_Expecter {
 	return &MockUrlSaver_Expecter{mock: &_m.Mock}
 }
 
+// FindAlias provides a mock function for the type MockUrlSaver
+func (_mock *MockUrlSaver) FindAlias(ctx context.Context, u string) (string, error) {
+	ret := _mock.Called(ctx, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAlias")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, u)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, u)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, u)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUrlSaver_FindAlias_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAlias'
+type MockUrlSaver_FindAlias_Call struct {
+	*mock.Call
+}
+
+// FindAlias is a helper method to define mock.On call
+//   - ctx context.Context
+//   - u string
+func (_e *MockUrlSaver_Expecter) FindAlias(ctx interface{}, u interface{}) *MockUrlSaver_FindAlias_Call {
+	return &MockUrlSaver_FindAlias_Call{Call: _e.mock.On("FindAlias", ctx, u)}
+}
+
+func (_c *MockUrlSaver_FindAlias_Call) Run(run func(ctx context.Context, u string)) *MockUrlSaver_FindAlias_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUrlSaver_FindAlias_Call) Return(s string, err error) *MockUrlSaver_FindAlias_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockUrlSaver_FindAlias_Call) RunAndReturn(run func(ctx context.Context, u string) (string, error)) *MockUrlSaver_FindAlias_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveURL provides a mock function for the type MockUrlSaver
-func (_mock *MockUrlSaver) SaveURL(ctx context.Context, originalURL string, alias string) error {
-	ret := _mock.Called(ctx, originalURL, alias)
+func (_mock *MockUrlSaver) SaveURL(ctx context.Context, link storage.Link) error {
+	ret := _mock.Called(ctx, link)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveURL")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = returnFunc(ctx, originalURL, alias)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, storage.Link) error); ok {
+		r0 = returnFunc(ctx, link)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -61,30 +128,24 @@ type MockUrlSaver_SaveURL_Call struct {
 
 // SaveURL is a helper method to define mock.On call
 //   - ctx context.Context
-//   - originalURL string
-//   - alias string
-func (_e *MockUrlSaver_Expecter) SaveURL(ctx interface{}, originalURL interface{}, alias interface{}) *MockUrlSaver_SaveURL_Call {
-	return &MockUrlSaver_SaveURL_Call{Call: _e.mock.On("SaveURL", ctx, originalURL, alias)}
+//   - link storage.Link
+func (_e *MockUrlSaver_Expecter) SaveURL(ctx interface{}, link interface{}) *MockUrlSaver_SaveURL_Call {
+	return &MockUrlSaver_SaveURL_Call{Call: _e.mock.On("SaveURL", ctx, link)}
 }
 
-func (_c *MockUrlSaver_SaveURL_Call) Run(run func(ctx context.Context, originalURL string, alias string)) *MockUrlSaver_SaveURL_Call {
+func (_c *MockUrlSaver_SaveURL_Call) Run(run func(ctx context.Context, link storage.Link)) *MockUrlSaver_SaveURL_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 storage.Link
 		if args[1] != nil {
-			arg1 = args[1].(string)
-		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
+			arg1 = args[1].(storage.Link)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
@@ -95,7 +156,7 @@ func (_c *MockUrlSaver_SaveURL_Call) Return(err error) *MockUrlSaver_SaveURL_Cal
 	return _c
 }
 
-func (_c *MockUrlSaver_SaveURL_Call) RunAndReturn(run func(ctx context.Context, originalURL string, alias string) error) *MockUrlSaver_SaveURL_Call {
+func (_c *MockUrlSaver_SaveURL_Call) RunAndReturn(run func(ctx context.Context, link storage.Link) error) *MockUrlSaver_SaveURL_Call {
 	_c.Call.Return(run)
 	return _c
 }
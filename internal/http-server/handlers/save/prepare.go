@@ -0,0 +1,81 @@
+package save
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+// rejection explains why a request was refused before reaching storage.
+type rejection struct {
+	status  int
+	message string
+	reason  string
+	err     error
+}
+
+// preparer runs the per-request checks shared by the single and batch
+// save handlers.
+type preparer struct {
+	validate      *validator.Validate
+	canonicalizer UrlCanonicalizer
+	destinations  DestinationPolicy
+}
+
+func newPreparer(policy AliasPolicy, canonicalizer UrlCanonicalizer, destinations DestinationPolicy) *preparer {
+	validate := validator.New()
+	if err := policy.Register(validate); err != nil {
+		panic(err)
+	}
+
+	return &preparer{
+		validate:      validate,
+		canonicalizer: canonicalizer,
+		destinations:  destinations,
+	}
+}
+
+// prepare validates req, vets and canonicalizes its destination and
+// resolves its expiration into the link that should be stored.
+func (p *preparer) prepare(ctx context.Context, req Request, now time.Time) (storage.Link, *rejection) {
+	if err := p.validate.Struct(req); err != nil {
+		var validateErr validator.ValidationErrors
+		if !errors.As(err, &validateErr) {
+			return storage.Link{}, &rejection{http.StatusBadRequest, "invalid request", "request validation failed", err}
+		}
+
+		return storage.Link{}, &rejection{http.StatusBadRequest,
+			response.ValidationError(validateErr).Error, "request validation failed", validateErr}
+	}
+
+	if p.destinations != nil {
+		if err := p.destinations.Check(ctx, req.URL); err != nil {
+			return storage.Link{}, &rejection{http.StatusUnprocessableEntity, err.Error(), "destination rejected by policy", err}
+		}
+	}
+
+	if p.canonicalizer != nil {
+		canonical, err := p.canonicalizer.Canonicalize(req.URL)
+		if err != nil {
+			return storage.Link{}, &rejection{http.StatusBadRequest, "field URL is not a valid URL", "failed to canonicalize URL", err}
+		}
+		req.URL = canonical
+	}
+
+	expiresAt, err := req.expiration(now)
+	if err != nil {
+		return storage.Link{}, &rejection{http.StatusBadRequest, err.Error(), "invalid expiration", err}
+	}
+
+	return storage.Link{
+		URL:       req.URL,
+		Alias:     req.Alias,
+		ExpiresAt: expiresAt,
+	}, nil
+}
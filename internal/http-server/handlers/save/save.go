@@ -8,35 +8,68 @@ import (
 	"log/slog"
 	"math/big"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 
+	"github.com/n0f4ph4mst3r/goshort/internal/alias"
 	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
 	"github.com/n0f4ph4mst3r/goshort/internal/sl"
 	"github.com/n0f4ph4mst3r/goshort/internal/storage"
 )
 
 type Request struct {
-	URL   string `json:"url" validate:"required,url"`
-	Alias string `json:"alias,omitempty"`
+	URL       string     `json:"url" validate:"required,url"`
+	Alias     string     `json:"alias,omitempty" validate:"omitempty,alias"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" validate:"omitempty,excluded_with=TTL"`
+	TTL       string     `json:"ttl,omitempty"`
+	Reuse     bool       `json:"reuse,omitempty" validate:"excluded_with=Alias ExpiresAt TTL"`
 }
 
 type Response struct {
 	response.Message
-	URL   string `json:"url,omitempty"`
-	Alias string `json:"alias,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	Alias     string     `json:"alias,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Reused    bool       `json:"reused,omitempty"`
 }
 
 type UrlSaver interface {
-	SaveURL(ctx context.Context, originalURL, alias string) error
+	SaveURL(ctx context.Context, link storage.Link) error
+	FindAlias(ctx context.Context, u string) (string, error)
 }
 
 type AliasGenerator interface {
-	Generate() string
+	Generate(ctx context.Context) (string, error)
 }
 
-func New(log *slog.Logger, saver UrlSaver, gen AliasGenerator) http.HandlerFunc {
+type AliasPolicy interface {
+	Register(v *validator.Validate) error
+	Check(alias string) error
+}
+
+type UrlCanonicalizer interface {
+	Canonicalize(raw string) (string, error)
+}
+
+type DestinationPolicy interface {
+	Check(ctx context.Context, raw string) error
+}
+
+// New returns the save handler. A nil canonicalizer stores destinations
+// exactly as they were sent, and a nil destination policy accepts any of
+// them.
+func New(log *slog.Logger, saver UrlSaver, gen AliasGenerator, policy AliasPolicy, canonicalizer UrlCanonicalizer, destinations DestinationPolicy) http.HandlerFunc {
+	if gen == nil {
+		gen = &DefaultRandomAlias{}
+	}
+	if policy == nil {
+		policy = &alias.Policy{}
+	}
+
+	prep := newPreparer(policy, canonicalizer, destinations)
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		log := sl.Init(log, r.Context(), "http-server.handlers.url.save.New")
 
@@ -59,18 +92,38 @@ func New(log *slog.Logger, saver UrlSaver, gen AliasGenerator) http.HandlerFunc
 
 		log.Info("request body decoded successfully", slog.Any("request", req))
 
-		if err := validator.New().Struct(req); err != nil {
-			validateErr := err.(validator.ValidationErrors)
-			sl.WriteResponse(log, w, r, http.StatusBadRequest,
-				response.ValidationError(validateErr),
-				"request validation failed", sl.Err(validateErr))
+		link, rej := prep.prepare(r.Context(), req, time.Now())
+		if rej != nil {
+			sl.WriteResponse(log, w, r, rej.status, response.Error(rej.message),
+				rej.reason, slog.String("url", req.URL), sl.Err(rej.err))
 
 			return
 		}
+		req.URL = link.URL
+
+		if req.Reuse {
+			existing, err := saver.FindAlias(r.Context(), req.URL)
+			if err == nil {
+				sl.WriteResponse(log, w, r, 0, Response{
+					Message: response.OK(),
+					URL:     req.URL,
+					Alias:   existing,
+					Reused:  true,
+				}, "existing alias reused", slog.String("url", req.URL), slog.String("alias", existing))
 
-		alias := req.Alias
-		if alias != "" {
-			err = saver.SaveURL(r.Context(), req.URL, alias)
+				return
+			}
+			if !errors.Is(err, storage.ErrUrlNotFound) {
+				sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+					response.Error("internal server error"),
+					"failed to look up existing alias", sl.Err(err))
+
+				return
+			}
+		}
+
+		if link.Alias != "" {
+			err = saver.SaveURL(r.Context(), link)
 			if errors.Is(err, storage.ErrUrlExists) {
 				sl.WriteResponse(log, w, r, http.StatusConflict,
 					response.Error("alias already exists"),
@@ -86,19 +139,27 @@ func New(log *slog.Logger, saver UrlSaver, gen AliasGenerator) http.HandlerFunc
 				return
 			}
 		} else {
-			if gen == nil {
-				gen = &DefaultRandomAlias{}
-			}
-
 			for attempt := 0; attempt < 10; attempt++ {
-				alias = gen.Generate()
+				link.Alias, err = gen.Generate(r.Context())
+				if err != nil {
+					sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+						response.Error("internal server error"),
+						"failed to generate alias", sl.Err(err))
 
-				err = saver.SaveURL(r.Context(), req.URL, alias)
+					return
+				}
+
+				if err = policy.Check(link.Alias); err != nil {
+					log.Warn("generated alias rejected by policy, regenerating", slog.String("alias", link.Alias), sl.Err(err))
+					continue
+				}
+
+				err = saver.SaveURL(r.Context(), link)
 				if err == nil {
 					break
 				}
 				if errors.Is(err, storage.ErrUrlExists) {
-					log.Warn("alias collision, regenerating", slog.String("alias", alias), slog.Int("attempt", attempt+1))
+					log.Warn("alias collision, regenerating", slog.String("alias", link.Alias), slog.Int("attempt", attempt+1))
 					continue
 				}
 
@@ -119,21 +180,62 @@ func New(log *slog.Logger, saver UrlSaver, gen AliasGenerator) http.HandlerFunc
 		}
 
 		sl.WriteResponse(log, w, r, 0, Response{
-			Message: response.OK(),
-			URL:     req.URL,
-			Alias:   alias,
-		}, "URL saved successfully", slog.String("url", req.URL), slog.String("alias", alias))
+			Message:   response.OK(),
+			URL:       link.URL,
+			Alias:     link.Alias,
+			ExpiresAt: link.ExpiresAt,
+		}, "URL saved successfully", slog.String("url", link.URL), slog.String("alias", link.Alias))
 	}
 }
 
-type DefaultRandomAlias struct{}
+var (
+	errInvalidTTL       = errors.New("field TTL is not a valid duration")
+	errExpirationInPast = errors.New("link expiration must be in the future")
+)
 
-func (g *DefaultRandomAlias) Generate() string {
-	const chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	buf := make([]byte, 6)
+func (req Request) expiration(now time.Time) (*time.Time, error) {
+	expiresAt := req.ExpiresAt
+
+	if req.TTL != "" {
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 {
+			return nil, errInvalidTTL
+		}
+		t := now.Add(ttl)
+		expiresAt = &t
+	}
+
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, errExpirationInPast
+	}
+
+	return expiresAt, nil
+}
+
+// DefaultRandomAlias draws aliases uniformly from Alphabet. The zero value
+// produces 6 characters of base62.
+type DefaultRandomAlias struct {
+	Alphabet string
+	Length   int
+}
+
+func (g *DefaultRandomAlias) Generate(_ context.Context) (string, error) {
+	chars := g.Alphabet
+	if chars == "" {
+		chars = alias.Base62
+	}
+	length := g.Length
+	if length <= 0 {
+		length = 6
+	}
+
+	buf := make([]byte, length)
 	for i := range buf {
-		num, _ := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+		num, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+		if err != nil {
+			return "", err
+		}
 		buf[i] = chars[num.Int64()]
 	}
-	return string(buf)
+	return string(buf), nil
 }
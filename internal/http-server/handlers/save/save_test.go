@@ -7,15 +7,22 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/n0f4ph4mst3r/goshort/internal/alias"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/destination"
 	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/save"
 	mocks "github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/save/mocks"
 	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
 	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	"github.com/n0f4ph4mst3r/goshort/internal/urlnorm"
 )
 
 const urlStr = "https://duckduckgo.com"
@@ -25,6 +32,8 @@ func TestSaveHandler(t *testing.T) {
 		name         string
 		alias        string
 		url          string
+		ttl          string
+		expiresAt    string
 		expectedCode int
 		respError    string
 		mockError    error
@@ -97,6 +106,45 @@ func TestSaveHandler(t *testing.T) {
 			expectedCode: http.StatusBadRequest,
 			respError:    "invalid request body",
 		},
+		{
+			name:         "TTL sets expiration",
+			alias:        "campaign",
+			url:          urlStr,
+			ttl:          "24h",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Explicit expiration",
+			alias:        "campaign",
+			url:          urlStr,
+			expiresAt:    "2999-01-01T00:00:00Z",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Invalid TTL",
+			alias:        "campaign",
+			url:          urlStr,
+			ttl:          "tomorrow",
+			expectedCode: http.StatusBadRequest,
+			respError:    "field TTL is not a valid duration",
+		},
+		{
+			name:         "Expiration in the past",
+			alias:        "campaign",
+			url:          urlStr,
+			expiresAt:    "2001-01-01T00:00:00Z",
+			expectedCode: http.StatusBadRequest,
+			respError:    "link expiration must be in the future",
+		},
+		{
+			name:         "Both TTL and expiration",
+			alias:        "campaign",
+			url:          urlStr,
+			ttl:          "24h",
+			expiresAt:    "2999-01-01T00:00:00Z",
+			expectedCode: http.StatusBadRequest,
+			respError:    "field ExpiresAt cannot be used together with TTL",
+		},
 	}
 
 	for _, tc := range cases {
@@ -112,34 +160,45 @@ func TestSaveHandler(t *testing.T) {
 				if tc.alias == "" {
 					switch tc.name {
 					case "Empty alias, generation succeeds after 6 collisions":
-						aliasGenMock.On("Generate").Return("collision").Times(6)
-						aliasGenMock.On("Generate").Return("unique_alias").Once()
+						aliasGenMock.On("Generate", mock.Anything).Return("collision", nil).Times(6)
+						aliasGenMock.On("Generate", mock.Anything).Return("unique_alias", nil).Once()
 
-						urlSaverMock.On("SaveURL", mock.Anything, tc.url, "collision").
+						urlSaverMock.On("SaveURL", mock.Anything, storage.Link{URL: tc.url, Alias: "collision"}).
 							Return(storage.ErrUrlExists).Times(6)
-						urlSaverMock.On("SaveURL", mock.Anything, tc.url, "unique_alias").
+						urlSaverMock.On("SaveURL", mock.Anything, storage.Link{URL: tc.url, Alias: "unique_alias"}).
 							Return(nil).Once()
 					case "Empty alias, generation fails after multiple attempts":
-						aliasGenMock.On("Generate").Return("collision").Times(10)
-						urlSaverMock.On("SaveURL", mock.Anything, tc.url, "collision").
+						aliasGenMock.On("Generate", mock.Anything).Return("collision", nil).Times(10)
+						urlSaverMock.On("SaveURL", mock.Anything, storage.Link{URL: tc.url, Alias: "collision"}).
 							Return(tc.mockError).Times(10)
 					default:
-						aliasGenMock.On("Generate").Return("random_alias").Once()
-						urlSaverMock.On("SaveURL", mock.Anything, tc.url, "random_alias").
+						aliasGenMock.On("Generate", mock.Anything).Return("random_alias", nil).Once()
+						urlSaverMock.On("SaveURL", mock.Anything, storage.Link{URL: tc.url, Alias: "random_alias"}).
 							Return(nil).Once()
 					}
+				} else if tc.ttl != "" || tc.expiresAt != "" {
+					urlSaverMock.On("SaveURL", mock.Anything, mock.MatchedBy(func(link storage.Link) bool {
+						return link.Alias == tc.alias && link.ExpiresAt != nil && link.ExpiresAt.After(time.Now())
+					})).Return(nil).Once()
 				} else {
-					urlSaverMock.On("SaveURL", mock.Anything, tc.url, tc.alias).
+					urlSaverMock.On("SaveURL", mock.Anything, storage.Link{URL: tc.url, Alias: tc.alias}).
 						Return(tc.mockError).Once()
 				}
 			}
 
-			handler := save.New(sldiscard.NewDiscardLogger(), urlSaverMock, aliasGenMock)
+			handler := save.New(sldiscard.NewDiscardLogger(), urlSaverMock, aliasGenMock, nil, nil, nil)
 
 			var input string
-			if tc.respError == "invalid request body" {
+			switch {
+			case tc.respError == "invalid request body":
 				input = `{"url": "missing_quote, "alias": "malformed"}`
-			} else {
+			case tc.ttl != "" && tc.expiresAt != "":
+				input = fmt.Sprintf(`{"url": "%s", "alias": "%s", "ttl": "%s", "expires_at": "%s"}`, tc.url, tc.alias, tc.ttl, tc.expiresAt)
+			case tc.ttl != "":
+				input = fmt.Sprintf(`{"url": "%s", "alias": "%s", "ttl": "%s"}`, tc.url, tc.alias, tc.ttl)
+			case tc.expiresAt != "":
+				input = fmt.Sprintf(`{"url": "%s", "alias": "%s", "expires_at": "%s"}`, tc.url, tc.alias, tc.expiresAt)
+			default:
 				input = fmt.Sprintf(`{"url": "%s", "alias": "%s"}`, tc.url, tc.alias)
 			}
 
@@ -158,3 +217,316 @@ func TestSaveHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestSaveHandler_Reuse(t *testing.T) {
+	cases := []struct {
+		name          string
+		input         string
+		findAlias     string
+		findError     error
+		expectedCode  int
+		expectedAlias string
+		reused        bool
+		respError     string
+	}{
+		{
+			name:          "Existing alias reused",
+			input:         fmt.Sprintf(`{"url": "%s", "reuse": true}`, urlStr),
+			findAlias:     "GoDuck",
+			expectedCode:  http.StatusOK,
+			expectedAlias: "GoDuck",
+			reused:        true,
+		},
+		{
+			name:          "No existing alias",
+			input:         fmt.Sprintf(`{"url": "%s", "reuse": true}`, urlStr),
+			findError:     storage.ErrUrlNotFound,
+			expectedCode:  http.StatusOK,
+			expectedAlias: "random_alias",
+		},
+		{
+			name:         "FindAlias Error",
+			input:        fmt.Sprintf(`{"url": "%s", "reuse": true}`, urlStr),
+			findError:    errors.New("unexpected error"),
+			expectedCode: http.StatusInternalServerError,
+			respError:    "internal server error",
+		},
+		{
+			name:         "Reuse with custom alias",
+			input:        fmt.Sprintf(`{"url": "%s", "alias": "GoDuck", "reuse": true}`, urlStr),
+			expectedCode: http.StatusBadRequest,
+			respError:    "field Reuse cannot be used together with Alias or ExpiresAt or TTL",
+		},
+		{
+			name:         "Reuse with TTL",
+			input:        fmt.Sprintf(`{"url": "%s", "ttl": "1h", "reuse": true}`, urlStr),
+			expectedCode: http.StatusBadRequest,
+			respError:    "field Reuse cannot be used together with Alias or ExpiresAt or TTL",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlSaverMock := mocks.NewMockUrlSaver(t)
+			aliasGenMock := mocks.NewMockAliasGenerator(t)
+
+			if tc.expectedCode != http.StatusBadRequest {
+				urlSaverMock.On("FindAlias", mock.Anything, urlStr).
+					Return(tc.findAlias, tc.findError).Once()
+			}
+			if errors.Is(tc.findError, storage.ErrUrlNotFound) {
+				aliasGenMock.On("Generate", mock.Anything).Return("random_alias", nil).Once()
+				urlSaverMock.On("SaveURL", mock.Anything, storage.Link{URL: urlStr, Alias: "random_alias"}).
+					Return(nil).Once()
+			}
+
+			handler := save.New(sldiscard.NewDiscardLogger(), urlSaverMock, aliasGenMock, nil, nil, nil)
+
+			req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(tc.input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			var resp save.Response
+			require.Equal(t, tc.expectedCode, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+			require.Equal(t, tc.expectedAlias, resp.Alias)
+			require.Equal(t, tc.reused, resp.Reused)
+		})
+	}
+}
+
+func TestSaveHandler_GeneratorError(t *testing.T) {
+	urlSaverMock := mocks.NewMockUrlSaver(t)
+	aliasGenMock := mocks.NewMockAliasGenerator(t)
+	aliasGenMock.On("Generate", mock.Anything).Return("", errors.New("sequence unavailable")).Once()
+
+	handler := save.New(sldiscard.NewDiscardLogger(), urlSaverMock, aliasGenMock, nil, nil, nil)
+
+	req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(fmt.Sprintf(`{"url": "%s"}`, urlStr))))
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var resp save.Response
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, "internal server error", resp.Error)
+}
+
+func TestSaveHandler_AliasPolicy(t *testing.T) {
+	blocklist := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(blocklist, []byte("# words\nbadword\n"), 0o600))
+
+	policy, err := alias.NewPolicy(&config.AliasPolicyConfig{
+		Charset:       "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_",
+		MinLength:     3,
+		MaxLength:     12,
+		Reserved:      []string{"api", "admin"},
+		BlocklistFile: blocklist,
+	})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name         string
+		alias        string
+		expectedCode int
+		respError    string
+	}{
+		{
+			name:         "Allowed alias",
+			alias:        "Go-Duck_1",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Too short",
+			alias:        "ab",
+			expectedCode: http.StatusBadRequest,
+			respError:    "field Alias must be at least 3 characters long",
+		},
+		{
+			name:         "Too long",
+			alias:        "abcdefghijklm",
+			expectedCode: http.StatusBadRequest,
+			respError:    "field Alias must be at most 12 characters long",
+		},
+		{
+			name:         "Slash",
+			alias:        "go/duck",
+			expectedCode: http.StatusBadRequest,
+			respError:    "field Alias contains characters that are not allowed",
+		},
+		{
+			name:         "Space",
+			alias:        "go duck",
+			expectedCode: http.StatusBadRequest,
+			respError:    "field Alias contains characters that are not allowed",
+		},
+		{
+			name:         "Reserved word",
+			alias:        "API",
+			expectedCode: http.StatusBadRequest,
+			respError:    "field Alias is a reserved word",
+		},
+		{
+			name:         "Blocked word",
+			alias:        "my-B4dw0rd",
+			expectedCode: http.StatusBadRequest,
+			respError:    "field Alias contains a blocked word",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlSaverMock := mocks.NewMockUrlSaver(t)
+			if tc.expectedCode == http.StatusOK {
+				urlSaverMock.On("SaveURL", mock.Anything, storage.Link{URL: urlStr, Alias: tc.alias}).
+					Return(nil).Once()
+			}
+
+			handler := save.New(sldiscard.NewDiscardLogger(), urlSaverMock, nil, policy, nil, nil)
+
+			input := fmt.Sprintf(`{"url": "%s", "alias": "%s"}`, urlStr, tc.alias)
+			req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			var resp save.Response
+			require.Equal(t, tc.expectedCode, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
+
+func TestSaveHandler_GeneratedAliasRejectedByPolicy(t *testing.T) {
+	policy, err := alias.NewPolicy(&config.AliasPolicyConfig{Reserved: []string{"admin"}})
+	require.NoError(t, err)
+
+	urlSaverMock := mocks.NewMockUrlSaver(t)
+	aliasGenMock := mocks.NewMockAliasGenerator(t)
+	aliasGenMock.On("Generate", mock.Anything).Return("admin", nil).Once()
+	aliasGenMock.On("Generate", mock.Anything).Return("GoDuck", nil).Once()
+	urlSaverMock.On("SaveURL", mock.Anything, storage.Link{URL: urlStr, Alias: "GoDuck"}).
+		Return(nil).Once()
+
+	handler := save.New(sldiscard.NewDiscardLogger(), urlSaverMock, aliasGenMock, policy, nil, nil)
+
+	req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(fmt.Sprintf(`{"url": "%s"}`, urlStr))))
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var resp save.Response
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, "GoDuck", resp.Alias)
+}
+
+func TestSaveHandler_CanonicalizesURL(t *testing.T) {
+	canonicalizer := urlnorm.New(&config.URLConfig{SortQuery: true})
+
+	cases := []struct {
+		name         string
+		url          string
+		canonical    string
+		expectedCode int
+		respError    string
+	}{
+		{
+			name:         "Canonical form is stored",
+			url:          "HTTP://Example.com:80/a/../b?b=2&a=1",
+			canonical:    "http://example.com/b?a=1&b=2",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Invalid host",
+			url:          "http://xn--a.example/",
+			expectedCode: http.StatusBadRequest,
+			respError:    "field URL is not a valid URL",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlSaverMock := mocks.NewMockUrlSaver(t)
+			if tc.expectedCode == http.StatusOK {
+				urlSaverMock.On("SaveURL", mock.Anything, storage.Link{URL: tc.canonical, Alias: "GoDuck"}).
+					Return(nil).Once()
+			}
+
+			handler := save.New(sldiscard.NewDiscardLogger(), urlSaverMock, nil, nil, canonicalizer, nil)
+
+			input := fmt.Sprintf(`{"url": "%s", "alias": "GoDuck"}`, tc.url)
+			req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			var resp save.Response
+			require.Equal(t, tc.expectedCode, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+			require.Equal(t, tc.canonical, resp.URL)
+		})
+	}
+}
+
+func TestSaveHandler_DestinationPolicy(t *testing.T) {
+	destinations, err := destination.NewPolicy(&config.DestinationConfig{
+		Schemes:   []string{"http", "https"},
+		SelfHosts: []string{"sho.rt"},
+	}, nil)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name      string
+		url       string
+		respError string
+	}{
+		{name: "Scheme", url: "javascript:alert(1)", respError: "destination scheme is not allowed"},
+		{name: "Loopback", url: "http://127.0.0.1/admin", respError: "destination points to a private or loopback address"},
+		{name: "Self reference", url: "https://sho.rt/abc", respError: "destination points back to this shortener"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := save.New(sldiscard.NewDiscardLogger(), mocks.NewMockUrlSaver(t), nil, nil, nil, destinations)
+
+			input := fmt.Sprintf(`{"url": "%s", "alias": "GoDuck"}`, tc.url)
+			req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			var resp save.Response
+			require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package stats_mocks
+
+import (
+	"context"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockStatsGetter creates a new instance of MockStatsGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockStatsGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockStatsGetter {
+	mock := &MockStatsGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockStatsGetter is an autogenerated mock type for the StatsGetter type
+type MockStatsGetter struct {
+	mock.Mock
+}
+
+type MockStatsGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockStatsGetter) EXPECT() *MockStatsGetter_Expecter {
+	return &MockStatsGetter_Expecter{mock: &_m.Mock}
+}
+
+// GetStats provides a mock function for the type MockStatsGetter
+func (_mock *MockStatsGetter) GetStats(ctx context.Context, q storage.StatsQuery) (storage.Stats, error) {
+	ret := _mock.Called(ctx, q)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStats")
+	}
+
+	var r0 storage.Stats
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, storage.StatsQuery) (storage.Stats, error)); ok {
+		return returnFunc(ctx, q)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, storage.StatsQuery) storage.Stats); ok {
+		r0 = returnFunc(ctx, q)
+	} else {
+		r0 = ret.Get(0).(storage.Stats)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, storage.StatsQuery) error); ok {
+		r1 = returnFunc(ctx, q)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStatsGetter_GetStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStats'
+type MockStatsGetter_GetStats_Call struct {
+	*mock.Call
+}
+
+// GetStats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - q storage.StatsQuery
+func (_e *MockStatsGetter_Expecter) GetStats(ctx interface{}, q interface{}) *MockStatsGetter_GetStats_Call {
+	return &MockStatsGetter_GetStats_Call{Call: _e.mock.On("GetStats", ctx, q)}
+}
+
+func (_c *MockStatsGetter_GetStats_Call) Run(run func(ctx context.Context, q storage.StatsQuery)) *MockStatsGetter_GetStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 storage.StatsQuery
+		if args[1] != nil {
+			arg1 = args[1].(storage.StatsQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStatsGetter_GetStats_Call) Return(stats storage.Stats, err error) *MockStatsGetter_GetStats_Call {
+	_c.Call.Return(stats, err)
+	return _c
+}
+
+func (_c *MockStatsGetter_GetStats_Call) RunAndReturn(run func(ctx context.Context, q storage.StatsQuery) (storage.Stats, error)) *MockStatsGetter_GetStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,135 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+const (
+	defaultRange = 7 * 24 * time.Hour
+	maxBuckets   = 24 * 366
+)
+
+type Response struct {
+	response.Message
+	Alias    string                `json:"alias,omitempty"`
+	From     time.Time             `json:"from"`
+	To       time.Time             `json:"to"`
+	Interval storage.StatsInterval `json:"interval,omitempty"`
+	storage.Stats
+}
+
+type StatsGetter interface {
+	GetStats(ctx context.Context, q storage.StatsQuery) (storage.Stats, error)
+}
+
+func New(log *slog.Logger, statsGetter StatsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.url.stats.New")
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("invalid request"),
+				"alias is empty")
+
+			return
+		}
+
+		q, err := parseQuery(r, alias, time.Now())
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error(err.Error()),
+				"invalid stats query", sl.Err(err))
+
+			return
+		}
+
+		stats, err := statsGetter.GetStats(r.Context(), q)
+		if errors.Is(err, storage.ErrUrlNotFound) {
+			sl.WriteResponse(log, w, r, http.StatusNotFound,
+				response.Error("invalid request"),
+				"URL not found", slog.String("alias", alias))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to get stats", sl.Err(err))
+
+			return
+		}
+
+		sl.WriteResponse(log, w, r, 0,
+			Response{
+				Message:  response.OK(),
+				Alias:    alias,
+				From:     q.From,
+				To:       q.To,
+				Interval: q.Interval,
+				Stats:    stats,
+			},
+			"stats collected", slog.String("alias", alias), slog.Int64("clicks", stats.TotalClicks))
+	}
+}
+
+var (
+	errInvalidFrom     = errors.New("parameter from is not a valid RFC 3339 time")
+	errInvalidTo       = errors.New("parameter to is not a valid RFC 3339 time")
+	errInvalidInterval = errors.New("parameter interval must be hour or day")
+	errInvalidRange    = errors.New("parameter from must be before to")
+	errRangeTooLarge   = errors.New("requested range has too many buckets")
+)
+
+func parseQuery(r *http.Request, alias string, now time.Time) (storage.StatsQuery, error) {
+	params := r.URL.Query()
+
+	q := storage.StatsQuery{
+		Alias:    alias,
+		To:       now.UTC(),
+		Interval: storage.IntervalDay,
+	}
+
+	if v := params.Get("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return storage.StatsQuery{}, errInvalidTo
+		}
+		q.To = to.UTC()
+	}
+
+	q.From = q.To.Add(-defaultRange)
+	if v := params.Get("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return storage.StatsQuery{}, errInvalidFrom
+		}
+		q.From = from.UTC()
+	}
+
+	if v := params.Get("interval"); v != "" {
+		q.Interval = storage.StatsInterval(v)
+		if q.Interval != storage.IntervalHour && q.Interval != storage.IntervalDay {
+			return storage.StatsQuery{}, errInvalidInterval
+		}
+	}
+
+	if !q.From.Before(q.To) {
+		return storage.StatsQuery{}, errInvalidRange
+	}
+	if q.To.Sub(q.From)/q.Interval.Duration() > maxBuckets {
+		return storage.StatsQuery{}, errRangeTooLarge
+	}
+
+	return q, nil
+}
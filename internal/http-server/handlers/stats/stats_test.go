@@ -0,0 +1,139 @@
+package stats_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/stats"
+	mocks "github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/stats/mocks"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+func TestStatsHandler(t *testing.T) {
+	from := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 11, 2, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name         string
+		alias        string
+		query        string
+		expectedCode int
+		respError    string
+		mockQuery    *storage.StatsQuery
+		mockStats    storage.Stats
+		mockError    error
+	}{
+		{
+			name:         "Success",
+			alias:        "GoDuck",
+			query:        "?from=2025-11-01T00:00:00Z&to=2025-11-02T00:00:00Z&interval=hour",
+			expectedCode: http.StatusOK,
+			mockQuery: &storage.StatsQuery{
+				Alias:    "GoDuck",
+				From:     from,
+				To:       to,
+				Interval: storage.IntervalHour,
+			},
+			mockStats: storage.Stats{
+				TotalClicks:    3,
+				UniqueVisitors: 2,
+				Buckets:        []storage.StatsBucket{{Start: from, Clicks: 3, UniqueVisitors: 2}},
+				TopReferrers:   []storage.StatsCount{{Value: "https://example.com", Clicks: 2}},
+				UserAgents:     []storage.StatsCount{{Value: "Firefox", Clicks: 3}},
+			},
+		},
+		{
+			name:         "Invalid from",
+			alias:        "GoDuck",
+			query:        "?from=yesterday",
+			expectedCode: http.StatusBadRequest,
+			respError:    "parameter from is not a valid RFC 3339 time",
+		},
+		{
+			name:         "Invalid interval",
+			alias:        "GoDuck",
+			query:        "?interval=minute",
+			expectedCode: http.StatusBadRequest,
+			respError:    "parameter interval must be hour or day",
+		},
+		{
+			name:         "Inverted range",
+			alias:        "GoDuck",
+			query:        "?from=2025-11-02T00:00:00Z&to=2025-11-01T00:00:00Z",
+			expectedCode: http.StatusBadRequest,
+			respError:    "parameter from must be before to",
+		},
+		{
+			name:         "Range too large",
+			alias:        "GoDuck",
+			query:        "?from=2000-01-01T00:00:00Z&to=2025-01-01T00:00:00Z&interval=hour",
+			expectedCode: http.StatusBadRequest,
+			respError:    "requested range has too many buckets",
+		},
+		{
+			name:         "URL not found",
+			alias:        "some_alias",
+			expectedCode: http.StatusNotFound,
+			respError:    "invalid request",
+			mockError:    storage.ErrUrlNotFound,
+		},
+		{
+			name:         "GetStats Error",
+			alias:        "some_alias",
+			expectedCode: http.StatusInternalServerError,
+			respError:    "internal error",
+			mockError:    errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			statsGetterMock := mocks.NewMockStatsGetter(t)
+
+			if tc.mockQuery != nil {
+				statsGetterMock.On("GetStats", mock.Anything, *tc.mockQuery).
+					Return(tc.mockStats, tc.mockError).Once()
+			} else if tc.respError == "" || tc.mockError != nil {
+				statsGetterMock.On("GetStats", mock.Anything, mock.MatchedBy(func(q storage.StatsQuery) bool {
+					return q.Alias == tc.alias && q.Interval == storage.IntervalDay && q.From.Before(q.To)
+				})).Return(tc.mockStats, tc.mockError).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Get("/url/{alias}/stats", stats.New(sldiscard.NewDiscardLogger(), statsGetterMock))
+
+			req, err := http.NewRequest(http.MethodGet, "/url/"+tc.alias+"/stats"+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+
+			var resp stats.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+
+			if tc.expectedCode == http.StatusOK {
+				require.Equal(t, tc.alias, resp.Alias)
+				require.Equal(t, tc.mockStats.TotalClicks, resp.TotalClicks)
+				require.Equal(t, tc.mockStats.UniqueVisitors, resp.UniqueVisitors)
+				require.Equal(t, tc.mockStats.TopReferrers, resp.TopReferrers)
+				require.Equal(t, tc.mockStats.UserAgents, resp.UserAgents)
+				require.Len(t, resp.Buckets, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package suggest_mocks
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockAliasChecker creates a new instance of MockAliasChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAliasChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAliasChecker {
+	mock := &MockAliasChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAliasChecker is an autogenerated mock type for the AliasChecker type
+type MockAliasChecker struct {
+	mock.Mock
+}
+
+type MockAliasChecker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAliasChecker) EXPECT() *MockAliasChecker_Expecter {
+	return &MockAliasChecker_Expecter{mock: &_m.Mock}
+}
+
+// TakenAliases provides a mock function for the type MockAliasChecker
+func (_mock *MockAliasChecker) TakenAliases(ctx context.Context, aliases []string) ([]string, error) {
+	ret := _mock.Called(ctx, aliases)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TakenAliases")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]string, error)); ok {
+		return returnFunc(ctx, aliases)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []string); ok {
+		r0 = returnFunc(ctx, aliases)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, aliases)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAliasChecker_TakenAliases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TakenAliases'
+type MockAliasChecker_TakenAliases_Call struct {
+	*mock.Call
+}
+
+// TakenAliases is a helper method to define mock.On call
+//   - ctx context.Context
+//   - aliases []string
+func (_e *MockAliasChecker_Expecter) TakenAliases(ctx interface{}, aliases interface{}) *MockAliasChecker_TakenAliases_Call {
+	return &MockAliasChecker_TakenAliases_Call{Call: _e.mock.On("TakenAliases", ctx, aliases)}
+}
+
+func (_c *MockAliasChecker_TakenAliases_Call) Run(run func(ctx context.Context, aliases []string)) *MockAliasChecker_TakenAliases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAliasChecker_TakenAliases_Call) Return(strings []string, err error) *MockAliasChecker_TakenAliases_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockAliasChecker_TakenAliases_Call) RunAndReturn(run func(ctx context.Context, aliases []string) ([]string, error)) *MockAliasChecker_TakenAliases_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,113 @@
+package suggest
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/alias"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+)
+
+const (
+	defaultLimit = 5
+	maxLimit     = 20
+)
+
+type Response struct {
+	response.Message
+	Base        string   `json:"base"`
+	Suggestions []string `json:"suggestions"`
+}
+
+type AliasChecker interface {
+	TakenAliases(ctx context.Context, aliases []string) ([]string, error)
+}
+
+type AliasPolicy interface {
+	Check(alias string) error
+}
+
+var (
+	errMissingBase  = errors.New("parameter base is required")
+	errInvalidLimit = errors.New("parameter limit must be between 1 and " + strconv.Itoa(maxLimit))
+)
+
+// New suggests available aliases close to the desired one. Candidates that
+// violate the alias policy are dropped, and the rest are checked against
+// storage in a single query.
+func New(log *slog.Logger, checker AliasChecker, policy AliasPolicy) http.HandlerFunc {
+	if policy == nil {
+		policy = &alias.Policy{}
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.alias.suggest.New")
+
+		params := r.URL.Query()
+
+		base := params.Get("base")
+		if base == "" {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error(errMissingBase.Error()),
+				"invalid suggest query", sl.Err(errMissingBase))
+
+			return
+		}
+
+		limit := defaultLimit
+		if v := params.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > maxLimit {
+				sl.WriteResponse(log, w, r, http.StatusBadRequest,
+					response.Error(errInvalidLimit.Error()),
+					"invalid suggest query", sl.Err(errInvalidLimit))
+
+				return
+			}
+			limit = n
+		}
+
+		var candidates []string
+		for _, candidate := range alias.Variants(base) {
+			if policy.Check(candidate) == nil {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		suggestions := []string{}
+		if len(candidates) > 0 {
+			taken, err := checker.TakenAliases(r.Context(), candidates)
+			if err != nil {
+				sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+					response.Error("internal error"),
+					"failed to check aliases", sl.Err(err))
+
+				return
+			}
+
+			used := make(map[string]struct{}, len(taken))
+			for _, a := range taken {
+				used[a] = struct{}{}
+			}
+
+			for _, candidate := range candidates {
+				if len(suggestions) == limit {
+					break
+				}
+				if _, ok := used[candidate]; !ok {
+					suggestions = append(suggestions, candidate)
+				}
+			}
+		}
+
+		sl.WriteResponse(log, w, r, 0, Response{
+			Message:     response.OK(),
+			Base:        base,
+			Suggestions: suggestions,
+		}, "aliases suggested", slog.String("base", base), slog.Int("count", len(suggestions)))
+	}
+}
@@ -0,0 +1,106 @@
+package suggest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/alias"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/suggest"
+	mocks "github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/suggest/mocks"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+)
+
+func TestSuggestHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		query        string
+		expectedCode int
+		respError    string
+		taken        []string
+		mockError    error
+		suggestions  []string
+	}{
+		{
+			name:         "Base is free",
+			query:        "?base=goduck",
+			expectedCode: http.StatusOK,
+			suggestions:  []string{"goduck", "goduck1", "goduck-1", "goduck-go", "go-goduck"},
+		},
+		{
+			name:         "Taken variants are skipped",
+			query:        "?base=goduck&limit=3",
+			expectedCode: http.StatusOK,
+			taken:        []string{"goduck", "goduck-1"},
+			suggestions:  []string{"goduck1", "goduck-go", "go-goduck"},
+		},
+		{
+			name:         "Policy drops invalid variants",
+			query:        "?base=admin&limit=2",
+			expectedCode: http.StatusOK,
+			suggestions:  []string{"admin1", "admin-1"},
+		},
+		{
+			name:         "Missing base",
+			expectedCode: http.StatusBadRequest,
+			respError:    "parameter base is required",
+		},
+		{
+			name:         "Invalid limit",
+			query:        "?base=goduck&limit=21",
+			expectedCode: http.StatusBadRequest,
+			respError:    "parameter limit must be between 1 and 20",
+		},
+		{
+			name:         "TakenAliases Error",
+			query:        "?base=goduck",
+			expectedCode: http.StatusInternalServerError,
+			respError:    "internal error",
+			mockError:    errors.New("unexpected error"),
+		},
+	}
+
+	policy, err := alias.NewPolicy(&config.AliasPolicyConfig{
+		Charset:  "abcdefghijklmnopqrstuvwxyz0123456789-",
+		Reserved: []string{"admin"},
+	})
+	require.NoError(t, err)
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			checkerMock := mocks.NewMockAliasChecker(t)
+
+			if tc.expectedCode != http.StatusBadRequest {
+				checkerMock.On("TakenAliases", mock.Anything, mock.Anything).
+					Return(tc.taken, tc.mockError).Once()
+			}
+
+			handler := suggest.New(sldiscard.NewDiscardLogger(), checkerMock, policy)
+
+			req, err := http.NewRequest(http.MethodGet, "/alias/suggest"+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+
+			var resp suggest.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+
+			if tc.expectedCode == http.StatusOK {
+				require.Equal(t, tc.suggestions, resp.Suggestions)
+			}
+		})
+	}
+}
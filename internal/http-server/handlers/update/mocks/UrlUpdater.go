@@ -0,0 +1,105 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package update_mocks
+
+import (
+	"context"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockUrlUpdater creates a new instance of MockUrlUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUrlUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUrlUpdater {
+	mock := &MockUrlUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUrlUpdater is an autogenerated mock type for the UrlUpdater type
+type MockUrlUpdater struct {
+	mock.Mock
+}
+
+type MockUrlUpdater_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUrlUpdater) EXPECT() *MockUrlUpdater_Expecter {
+	return &MockUrlUpdater_Expecter{mock: &_m.Mock}
+}
+
+// UpdateURL provides a mock function for the type MockUrlUpdater
+func (_mock *MockUrlUpdater) UpdateURL(ctx context.Context, upd storage.Update) (storage.Change, error) {
+	ret := _mock.Called(ctx, upd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateURL")
+	}
+
+	var r0 storage.Change
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, storage.Update) (storage.Change, error)); ok {
+		return returnFunc(ctx, upd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, storage.Update) storage.Change); ok {
+		r0 = returnFunc(ctx, upd)
+	} else {
+		r0 = ret.Get(0).(storage.Change)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, storage.Update) error); ok {
+		r1 = returnFunc(ctx, upd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUrlUpdater_UpdateURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateURL'
+type MockUrlUpdater_UpdateURL_Call struct {
+	*mock.Call
+}
+
+// UpdateURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - upd storage.Update
+func (_e *MockUrlUpdater_Expecter) UpdateURL(ctx interface{}, upd interface{}) *MockUrlUpdater_UpdateURL_Call {
+	return &MockUrlUpdater_UpdateURL_Call{Call: _e.mock.On("UpdateURL", ctx, upd)}
+}
+
+func (_c *MockUrlUpdater_UpdateURL_Call) Run(run func(ctx context.Context, upd storage.Update)) *MockUrlUpdater_UpdateURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 storage.Update
+		if args[1] != nil {
+			arg1 = args[1].(storage.Update)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUrlUpdater_UpdateURL_Call) Return(change storage.Change, err error) *MockUrlUpdater_UpdateURL_Call {
+	_c.Call.Return(change, err)
+	return _c
+}
+
+func (_c *MockUrlUpdater_UpdateURL_Call) RunAndReturn(run func(ctx context.Context, upd storage.Update) (storage.Change, error)) *MockUrlUpdater_UpdateURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,188 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+type Request struct {
+	URL             string     `json:"url,omitempty" validate:"omitempty,url"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty" validate:"omitempty,excluded_with=TTL ClearExpiration"`
+	TTL             string     `json:"ttl,omitempty" validate:"excluded_with=ClearExpiration"`
+	ClearExpiration bool       `json:"clear_expiration,omitempty"`
+}
+
+type Response struct {
+	response.Message
+	Alias       string     `json:"alias,omitempty"`
+	Version     int64      `json:"version,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	PreviousURL string     `json:"previous_url,omitempty"`
+}
+
+type UrlUpdater interface {
+	UpdateURL(ctx context.Context, upd storage.Update) (storage.Change, error)
+}
+
+type UrlCanonicalizer interface {
+	Canonicalize(raw string) (string, error)
+}
+
+type DestinationPolicy interface {
+	Check(ctx context.Context, raw string) error
+}
+
+func New(log *slog.Logger, urlUpdater UrlUpdater, canonicalizer UrlCanonicalizer, destinations DestinationPolicy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := sl.Init(log, r.Context(), "http-server.handlers.url.update.New")
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("invalid request"),
+				"alias is empty")
+
+			return
+		}
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if errors.Is(err, io.EOF) {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("empty request"),
+				"request body is empty")
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("invalid request body"),
+				"failed to decode request body", sl.Err(err))
+
+			return
+		}
+
+		log.Info("request body decoded successfully", slog.Any("request", req))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.ValidationError(validateErr),
+				"request validation failed", sl.Err(validateErr))
+
+			return
+		}
+
+		if req.URL == "" && req.ExpiresAt == nil && req.TTL == "" && !req.ClearExpiration {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error("nothing to update"),
+				"request has no changes")
+
+			return
+		}
+
+		if req.URL != "" && destinations != nil {
+			if err := destinations.Check(r.Context(), req.URL); err != nil {
+				sl.WriteResponse(log, w, r, http.StatusUnprocessableEntity,
+					response.Error(err.Error()),
+					"destination rejected by policy", slog.String("url", req.URL), sl.Err(err))
+
+				return
+			}
+		}
+
+		if req.URL != "" && canonicalizer != nil {
+			canonical, err := canonicalizer.Canonicalize(req.URL)
+			if err != nil {
+				sl.WriteResponse(log, w, r, http.StatusBadRequest,
+					response.Error("field URL is not a valid URL"),
+					"failed to canonicalize URL", sl.Err(err))
+
+				return
+			}
+			req.URL = canonical
+		}
+
+		expiresAt, err := req.expiration(time.Now())
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusBadRequest,
+				response.Error(err.Error()),
+				"invalid expiration", sl.Err(err))
+
+			return
+		}
+
+		principal, _ := auth.PrincipalFrom(r.Context())
+		change, err := urlUpdater.UpdateURL(r.Context(), storage.Update{
+			Alias:          alias,
+			URL:            req.URL,
+			ExpiresAt:      expiresAt,
+			ClearExpiresAt: req.ClearExpiration,
+			ChangedBy:      principal.Name,
+		})
+		if errors.Is(err, storage.ErrUrlNotFound) {
+			sl.WriteResponse(log, w, r, http.StatusNotFound,
+				response.Error("invalid request"),
+				"URL not found", slog.String("alias", alias))
+
+			return
+		}
+		if err != nil {
+			sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+				response.Error("internal error"),
+				"failed to update URL", sl.Err(err))
+
+			return
+		}
+
+		sl.WriteResponse(log, w, r, 0,
+			Response{
+				Message:     response.OK(),
+				Alias:       alias,
+				Version:     change.Version,
+				URL:         change.NewURL,
+				ExpiresAt:   change.NewExpiresAt,
+				PreviousURL: change.OldURL,
+			},
+			"URL updated", slog.String("alias", alias), slog.Int64("version", change.Version),
+			slog.String("url", change.NewURL), slog.String("previous_url", change.OldURL))
+	}
+}
+
+var (
+	errInvalidTTL       = errors.New("field TTL is not a valid duration")
+	errExpirationInPast = errors.New("link expiration must be in the future")
+)
+
+func (req Request) expiration(now time.Time) (*time.Time, error) {
+	expiresAt := req.ExpiresAt
+
+	if req.TTL != "" {
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 {
+			return nil, errInvalidTTL
+		}
+		t := now.Add(ttl)
+		expiresAt = &t
+	}
+
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, errExpirationInPast
+	}
+
+	return expiresAt, nil
+}
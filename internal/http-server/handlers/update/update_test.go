@@ -0,0 +1,133 @@
+package update_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/update"
+	mocks "github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/update/mocks"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+func TestUpdateHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		alias        string
+		body         string
+		update       *storage.Update
+		mockChange   storage.Change
+		expectedCode int
+		mockError    error
+	}{
+		{
+			name:   "Success",
+			alias:  "some_alias",
+			body:   `{"url": "https://duckduckgo.com/new"}`,
+			update: &storage.Update{Alias: "some_alias", URL: "https://duckduckgo.com/new"},
+			mockChange: storage.Change{
+				Version: 1,
+				OldURL:  "https://duckduckgo.com",
+				NewURL:  "https://duckduckgo.com/new",
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "Clear expiration",
+			alias:  "some_alias",
+			body:   `{"clear_expiration": true}`,
+			update: &storage.Update{Alias: "some_alias", ClearExpiresAt: true},
+			mockChange: storage.Change{
+				Version: 2,
+				OldURL:  "https://duckduckgo.com",
+				NewURL:  "https://duckduckgo.com",
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Nothing to update",
+			alias:        "some_alias",
+			body:         `{}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "TTL with clear expiration",
+			alias:        "some_alias",
+			body:         `{"ttl": "1h", "clear_expiration": true}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Expiration in the past",
+			alias:        "some_alias",
+			body:         `{"expires_at": "2000-01-01T00:00:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Empty body",
+			alias:        "some_alias",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Invalid URL",
+			alias:        "some_alias",
+			body:         `{"url": "not a url"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "URL Not Found",
+			alias:        "some_alias",
+			body:         `{"url": "https://duckduckgo.com/new"}`,
+			update:       &storage.Update{Alias: "some_alias", URL: "https://duckduckgo.com/new"},
+			expectedCode: http.StatusNotFound,
+			mockError:    storage.ErrUrlNotFound,
+		},
+		{
+			name:         "UpdateURL Error",
+			alias:        "some_alias",
+			body:         `{"url": "https://duckduckgo.com/new"}`,
+			update:       &storage.Update{Alias: "some_alias", URL: "https://duckduckgo.com/new"},
+			expectedCode: http.StatusInternalServerError,
+			mockError:    errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlUpdaterMock := mocks.NewMockUrlUpdater(t)
+
+			if tc.update != nil {
+				urlUpdaterMock.On("UpdateURL", mock.Anything, *tc.update).
+					Return(tc.mockChange, tc.mockError).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Patch("/url/{alias}", update.New(sldiscard.NewDiscardLogger(), urlUpdaterMock, nil, nil))
+
+			req, err := http.NewRequest(http.MethodPatch, "/url/"+tc.alias, bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusOK {
+				var resp update.Response
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tc.mockChange.Version, resp.Version)
+				require.Equal(t, tc.mockChange.NewURL, resp.URL)
+				require.Equal(t, tc.mockChange.OldURL, resp.PreviousURL)
+			}
+		})
+	}
+}
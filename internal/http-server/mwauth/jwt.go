@@ -0,0 +1,103 @@
+package mwauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/jwt"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (jwt.Claims, error)
+}
+
+type UserResolver interface {
+	GetUser(ctx context.Context, name string) (storage.User, error)
+	CreateUser(ctx context.Context, name string) (storage.User, error)
+}
+
+// NewJWT returns a middleware that authenticates requests by an
+// `Authorization: Bearer` JWT and puts the principal into the request
+// context. The user claim names the principal, which is created as a user
+// on first sight so that it can own links; the scopes claim is filtered
+// down to the scopes known here.
+func NewJWT(log *slog.Logger, verifier TokenVerifier, users UserResolver, cfg *config.JWTConfig) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/auth"),
+		)
+		ids := &userIDs{users: users}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !strings.EqualFold(scheme, "Bearer") || token == "" {
+				unauthorized(log, w, r, Credentials{}, "unauthorized")
+				return
+			}
+
+			claims, err := verifier.Verify(r.Context(), strings.TrimSpace(token))
+			if err != nil {
+				unauthorized(log, w, r, Credentials{}, "invalid token: "+err.Error())
+				return
+			}
+
+			name := claims.String(cfg.UserClaim)
+			if name == "" {
+				unauthorized(log, w, r, Credentials{}, fmt.Sprintf("invalid token: missing %s claim", cfg.UserClaim))
+				return
+			}
+
+			id, err := ids.resolve(r.Context(), name)
+			if err != nil {
+				sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+					response.Error("internal error"),
+					"failed to resolve token user", slog.String("user", name), sl.Err(err))
+				return
+			}
+
+			principal := auth.Principal{
+				UserID: id,
+				Name:   name,
+				Scopes: auth.FilterScopes(claims.Strings(cfg.ScopesClaim)),
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// userIDs remembers the ids of token users, creating users it has not seen.
+type userIDs struct {
+	users UserResolver
+	known sync.Map
+}
+
+func (u *userIDs) resolve(ctx context.Context, name string) (int64, error) {
+	if id, ok := u.known.Load(name); ok {
+		return id.(int64), nil
+	}
+
+	user, err := u.users.GetUser(ctx, name)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		user, err = u.users.CreateUser(ctx, name)
+		// Another instance may have created the user in the meantime.
+		if errors.Is(err, storage.ErrUserExists) {
+			user, err = u.users.GetUser(ctx, name)
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	u.known.Store(name, user.ID)
+	return user.ID, nil
+}
@@ -0,0 +1,169 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mwauth_mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockKeyFinder creates a new instance of MockKeyFinder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockKeyFinder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockKeyFinder {
+	mock := &MockKeyFinder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockKeyFinder is an autogenerated mock type for the KeyFinder type
+type MockKeyFinder struct {
+	mock.Mock
+}
+
+type MockKeyFinder_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockKeyFinder) EXPECT() *MockKeyFinder_Expecter {
+	return &MockKeyFinder_Expecter{mock: &_m.Mock}
+}
+
+// FindAPIKey provides a mock function for the type MockKeyFinder
+func (_mock *MockKeyFinder) FindAPIKey(ctx context.Context, hash string) (storage.APIKey, error) {
+	ret := _mock.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAPIKey")
+	}
+
+	var r0 storage.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (storage.APIKey, error)); ok {
+		return returnFunc(ctx, hash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) storage.APIKey); ok {
+		r0 = returnFunc(ctx, hash)
+	} else {
+		r0 = ret.Get(0).(storage.APIKey)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKeyFinder_FindAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAPIKey'
+type MockKeyFinder_FindAPIKey_Call struct {
+	*mock.Call
+}
+
+// FindAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+func (_e *MockKeyFinder_Expecter) FindAPIKey(ctx interface{}, hash interface{}) *MockKeyFinder_FindAPIKey_Call {
+	return &MockKeyFinder_FindAPIKey_Call{Call: _e.mock.On("FindAPIKey", ctx, hash)}
+}
+
+func (_c *MockKeyFinder_FindAPIKey_Call) Run(run func(ctx context.Context, hash string)) *MockKeyFinder_FindAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKeyFinder_FindAPIKey_Call) Return(aPIKey storage.APIKey, err error) *MockKeyFinder_FindAPIKey_Call {
+	_c.Call.Return(aPIKey, err)
+	return _c
+}
+
+func (_c *MockKeyFinder_FindAPIKey_Call) RunAndReturn(run func(ctx context.Context, hash string) (storage.APIKey, error)) *MockKeyFinder_FindAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TouchAPIKey provides a mock function for the type MockKeyFinder
+func (_mock *MockKeyFinder) TouchAPIKey(ctx context.Context, id int64, at time.Time) error {
+	ret := _mock.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchAPIKey")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockKeyFinder_TouchAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TouchAPIKey'
+type MockKeyFinder_TouchAPIKey_Call struct {
+	*mock.Call
+}
+
+// TouchAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - at time.Time
+func (_e *MockKeyFinder_Expecter) TouchAPIKey(ctx interface{}, id interface{}, at interface{}) *MockKeyFinder_TouchAPIKey_Call {
+	return &MockKeyFinder_TouchAPIKey_Call{Call: _e.mock.On("TouchAPIKey", ctx, id, at)}
+}
+
+func (_c *MockKeyFinder_TouchAPIKey_Call) Run(run func(ctx context.Context, id int64, at time.Time)) *MockKeyFinder_TouchAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKeyFinder_TouchAPIKey_Call) Return(err error) *MockKeyFinder_TouchAPIKey_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockKeyFinder_TouchAPIKey_Call) RunAndReturn(run func(ctx context.Context, id int64, at time.Time) error) *MockKeyFinder_TouchAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,171 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mwauth_mocks
+
+import (
+	"context"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockUserResolver creates a new instance of MockUserResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserResolver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserResolver {
+	mock := &MockUserResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUserResolver is an autogenerated mock type for the UserResolver type
+type MockUserResolver struct {
+	mock.Mock
+}
+
+type MockUserResolver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserResolver) EXPECT() *MockUserResolver_Expecter {
+	return &MockUserResolver_Expecter{mock: &_m.Mock}
+}
+
+// CreateUser provides a mock function for the type MockUserResolver
+func (_mock *MockUserResolver) CreateUser(ctx context.Context, name string) (storage.User, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 storage.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (storage.User, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) storage.User); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		r0 = ret.Get(0).(storage.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserResolver_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
+type MockUserResolver_CreateUser_Call struct {
+	*mock.Call
+}
+
+// CreateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockUserResolver_Expecter) CreateUser(ctx interface{}, name interface{}) *MockUserResolver_CreateUser_Call {
+	return &MockUserResolver_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, name)}
+}
+
+func (_c *MockUserResolver_CreateUser_Call) Run(run func(ctx context.Context, name string)) *MockUserResolver_CreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserResolver_CreateUser_Call) Return(user storage.User, err error) *MockUserResolver_CreateUser_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserResolver_CreateUser_Call) RunAndReturn(run func(ctx context.Context, name string) (storage.User, error)) *MockUserResolver_CreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUser provides a mock function for the type MockUserResolver
+func (_mock *MockUserResolver) GetUser(ctx context.Context, name string) (storage.User, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 storage.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (storage.User, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) storage.User); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		r0 = ret.Get(0).(storage.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserResolver_GetUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUser'
+type MockUserResolver_GetUser_Call struct {
+	*mock.Call
+}
+
+// GetUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockUserResolver_Expecter) GetUser(ctx interface{}, name interface{}) *MockUserResolver_GetUser_Call {
+	return &MockUserResolver_GetUser_Call{Call: _e.mock.On("GetUser", ctx, name)}
+}
+
+func (_c *MockUserResolver_GetUser_Call) Run(run func(ctx context.Context, name string)) *MockUserResolver_GetUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserResolver_GetUser_Call) Return(user storage.User, err error) *MockUserResolver_GetUser_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserResolver_GetUser_Call) RunAndReturn(run func(ctx context.Context, name string) (storage.User, error)) *MockUserResolver_GetUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,133 @@
+package mwauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+// touchInterval limits how often the last use of a key is written back.
+const touchInterval = time.Minute
+
+type KeyFinder interface {
+	FindAPIKey(ctx context.Context, hash string) (storage.APIKey, error)
+	TouchAPIKey(ctx context.Context, id int64, at time.Time) error
+}
+
+// Credentials is the legacy BasicAuth pair. It is accepted next to API
+// keys so that the first keys can be issued, unless User or Password is
+// empty, and authenticates an admin.
+type Credentials struct {
+	User     string
+	Password string
+}
+
+func (c Credentials) enabled() bool {
+	return c.User != "" && c.Password != ""
+}
+
+// New returns a middleware that authenticates requests by an
+// `Authorization: Bearer` API key, or by the BasicAuth pair if one is
+// configured, and puts the principal into the request context.
+func New(log *slog.Logger, keys KeyFinder, basic Credentials) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/auth"),
+		)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			scheme, credential, _ := strings.Cut(header, " ")
+
+			switch {
+			case strings.EqualFold(scheme, "Bearer") && credential != "":
+				principal, err := authenticateKey(r.Context(), log, keys, strings.TrimSpace(credential))
+				if errors.Is(err, errInvalidKey) || errors.Is(err, errRevokedKey) {
+					unauthorized(log, w, r, basic, err.Error())
+					return
+				}
+				if err != nil {
+					sl.WriteResponse(log, w, r, http.StatusInternalServerError,
+						response.Error("internal error"),
+						"failed to look up API key", sl.Err(err))
+					return
+				}
+
+				next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+
+			case strings.EqualFold(scheme, "Basic") && basic.enabled():
+				user, password, ok := r.BasicAuth()
+				if !ok || !equal(user, basic.User) || !equal(password, basic.Password) {
+					unauthorized(log, w, r, basic, "invalid credentials")
+					return
+				}
+
+				principal := auth.Principal{Name: user, Scopes: []string{auth.ScopeAdmin}}
+				next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+
+			default:
+				unauthorized(log, w, r, basic, "unauthorized")
+			}
+		})
+	}
+}
+
+var (
+	errInvalidKey = errors.New("invalid API key")
+	errRevokedKey = errors.New("API key has been revoked")
+)
+
+func authenticateKey(ctx context.Context, log *slog.Logger, keys KeyFinder, plain string) (auth.Principal, error) {
+	if !strings.HasPrefix(plain, auth.KeyPrefix) {
+		return auth.Principal{}, errInvalidKey
+	}
+
+	key, err := keys.FindAPIKey(ctx, auth.HashKey(plain))
+	if errors.Is(err, storage.ErrKeyNotFound) {
+		return auth.Principal{}, errInvalidKey
+	}
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	if key.RevokedAt != nil {
+		return auth.Principal{}, errRevokedKey
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= touchInterval {
+		if err := keys.TouchAPIKey(ctx, key.ID, now); err != nil {
+			log.Warn("failed to record API key use", slog.Int64("key_id", key.ID), sl.Err(err))
+		}
+	}
+
+	return auth.Principal{
+		UserID: key.UserID,
+		Name:   key.UserName,
+		KeyID:  key.ID,
+		Scopes: key.Scopes,
+	}, nil
+}
+
+func unauthorized(log *slog.Logger, w http.ResponseWriter, r *http.Request, basic Credentials, msg string) {
+	w.Header().Add("WWW-Authenticate", `Bearer realm="goshort"`)
+	if basic.enabled() {
+		w.Header().Add("WWW-Authenticate", `Basic realm="goshort"`)
+	}
+
+	sl.WriteResponse(log, w, r, http.StatusUnauthorized,
+		response.Error(msg),
+		"request not authenticated", slog.String("reason", msg))
+}
+
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
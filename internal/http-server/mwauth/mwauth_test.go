@@ -0,0 +1,337 @@
+package mwauth_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/mwauth"
+	mocks "github.com/n0f4ph4mst3r/goshort/internal/http-server/mwauth/mocks"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/jwt"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+const key = "gs_0123456789abcdefghijABCDEFGHIJ0123456789"
+
+func TestAuthMiddleware(t *testing.T) {
+	recent := time.Now().Add(-time.Second)
+	revoked := time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		name         string
+		basic        mwauth.Credentials
+		setup        func(r *http.Request)
+		found        *storage.APIKey
+		findError    error
+		touch        bool
+		expectedCode int
+		respError    string
+		principal    auth.Principal
+	}{
+		{
+			name:         "No credentials",
+			setup:        func(r *http.Request) {},
+			expectedCode: http.StatusUnauthorized,
+			respError:    "unauthorized",
+		},
+		{
+			name:         "Valid key",
+			setup:        func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key) },
+			found:        &storage.APIKey{ID: 7, UserID: 3, UserName: "alice", Scopes: []string{"links:read"}},
+			touch:        true,
+			expectedCode: http.StatusOK,
+			principal:    auth.Principal{UserID: 3, Name: "alice", KeyID: 7, Scopes: []string{"links:read"}},
+		},
+		{
+			name:         "Recently used key is not touched",
+			setup:        func(r *http.Request) { r.Header.Set("Authorization", "bearer "+key) },
+			found:        &storage.APIKey{ID: 7, UserID: 3, UserName: "alice", LastUsedAt: &recent},
+			expectedCode: http.StatusOK,
+			principal:    auth.Principal{UserID: 3, Name: "alice", KeyID: 7},
+		},
+		{
+			name:         "Unknown key",
+			setup:        func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key) },
+			findError:    storage.ErrKeyNotFound,
+			expectedCode: http.StatusUnauthorized,
+			respError:    "invalid API key",
+		},
+		{
+			name:         "Foreign token",
+			setup:        func(r *http.Request) { r.Header.Set("Authorization", "Bearer eyJhbGciOi") },
+			expectedCode: http.StatusUnauthorized,
+			respError:    "invalid API key",
+		},
+		{
+			name:         "Revoked key",
+			setup:        func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key) },
+			found:        &storage.APIKey{ID: 7, UserID: 3, RevokedAt: &revoked},
+			expectedCode: http.StatusUnauthorized,
+			respError:    "API key has been revoked",
+		},
+		{
+			name:         "Storage failure",
+			setup:        func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key) },
+			findError:    errors.New("connection reset"),
+			expectedCode: http.StatusInternalServerError,
+			respError:    "internal error",
+		},
+		{
+			name:         "Basic pair",
+			basic:        mwauth.Credentials{User: "admin", Password: "secret"},
+			setup:        func(r *http.Request) { r.SetBasicAuth("admin", "secret") },
+			expectedCode: http.StatusOK,
+			principal:    auth.Principal{Name: "admin", Scopes: []string{auth.ScopeAdmin}},
+		},
+		{
+			name:         "Wrong basic password",
+			basic:        mwauth.Credentials{User: "admin", Password: "secret"},
+			setup:        func(r *http.Request) { r.SetBasicAuth("admin", "guess") },
+			expectedCode: http.StatusUnauthorized,
+			respError:    "invalid credentials",
+		},
+		{
+			name:         "Basic pair not configured",
+			setup:        func(r *http.Request) { r.SetBasicAuth("admin", "") },
+			expectedCode: http.StatusUnauthorized,
+			respError:    "unauthorized",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyFinderMock := mocks.NewMockKeyFinder(t)
+			if tc.found != nil || tc.findError != nil {
+				found := storage.APIKey{}
+				if tc.found != nil {
+					found = *tc.found
+				}
+				keyFinderMock.On("FindAPIKey", mock.Anything, auth.HashKey(key)).Return(found, tc.findError).Once()
+			}
+			if tc.touch {
+				keyFinderMock.On("TouchAPIKey", mock.Anything, tc.found.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+			}
+
+			var got auth.Principal
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var ok bool
+				got, ok = auth.PrincipalFrom(r.Context())
+				require.True(t, ok)
+			})
+			handler := mwauth.New(sldiscard.NewDiscardLogger(), keyFinderMock, tc.basic)(next)
+
+			req, err := http.NewRequest(http.MethodGet, "/api/url", nil)
+			require.NoError(t, err)
+			tc.setup(req)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.respError != "" {
+				var resp response.Message
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tc.respError, resp.Error)
+				return
+			}
+			require.Equal(t, tc.principal, got)
+		})
+	}
+}
+
+func TestAuthMiddleware_Challenge(t *testing.T) {
+	handler := mwauth.New(sldiscard.NewDiscardLogger(), mocks.NewMockKeyFinder(t), mwauth.Credentials{User: "admin", Password: "secret"})(http.NotFoundHandler())
+
+	req, err := http.NewRequest(http.MethodGet, "/api/url", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	require.Equal(t, []string{`Bearer realm="goshort"`, `Basic realm="goshort"`}, rr.Header().Values("WWW-Authenticate"))
+}
+
+func TestRequire(t *testing.T) {
+	cases := []struct {
+		name         string
+		principal    *auth.Principal
+		expectedCode int
+	}{
+		{name: "Granted", principal: &auth.Principal{Scopes: []string{"links:read", "links:delete"}}, expectedCode: http.StatusOK},
+		{name: "Admin", principal: &auth.Principal{Scopes: []string{auth.ScopeAdmin}}, expectedCode: http.StatusOK},
+		{name: "Missing", principal: &auth.Principal{Scopes: []string{"links:read"}}, expectedCode: http.StatusForbidden},
+		{name: "No principal", expectedCode: http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			handler := mwauth.Require(sldiscard.NewDiscardLogger(), auth.ScopeLinksDelete)(next)
+
+			req, err := http.NewRequest(http.MethodDelete, "/api/url/abc", nil)
+			require.NoError(t, err)
+			if tc.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *tc.principal))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusForbidden {
+				var resp response.Message
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, "missing scope links:delete", resp.Error)
+				require.Contains(t, rr.Header().Get("WWW-Authenticate"), `error="insufficient_scope"`)
+			}
+		})
+	}
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	cfg := &config.JWTConfig{UserClaim: "sub", ScopesClaim: "scope"}
+
+	cases := []struct {
+		name         string
+		header       string
+		claims       jwt.Claims
+		verifyError  error
+		getError     error
+		create       bool
+		createError  error
+		expectedCode int
+		respError    string
+		principal    auth.Principal
+	}{
+		{
+			name:         "No token",
+			expectedCode: http.StatusUnauthorized,
+			respError:    "unauthorized",
+		},
+		{
+			name:         "Known user",
+			header:       "Bearer token",
+			claims:       jwt.Claims{"sub": "alice", "scope": "links:read openid"},
+			expectedCode: http.StatusOK,
+			principal:    auth.Principal{UserID: 3, Name: "alice", Scopes: []string{"links:read"}},
+		},
+		{
+			name:         "New user",
+			header:       "Bearer token",
+			claims:       jwt.Claims{"sub": "alice", "scope": []any{"links:write", "admin"}},
+			getError:     storage.ErrUserNotFound,
+			create:       true,
+			expectedCode: http.StatusOK,
+			principal:    auth.Principal{UserID: 3, Name: "alice", Scopes: []string{"admin", "links:write"}},
+		},
+		{
+			name:         "Invalid token",
+			header:       "Bearer token",
+			verifyError:  jwt.ErrExpired,
+			expectedCode: http.StatusUnauthorized,
+			respError:    "invalid token: token has expired",
+		},
+		{
+			name:         "Missing user claim",
+			header:       "Bearer token",
+			claims:       jwt.Claims{"scope": "links:read"},
+			expectedCode: http.StatusUnauthorized,
+			respError:    "invalid token: missing sub claim",
+		},
+		{
+			name:         "Storage failure",
+			header:       "Bearer token",
+			claims:       jwt.Claims{"sub": "alice"},
+			getError:     storage.ErrUserNotFound,
+			create:       true,
+			createError:  errors.New("connection reset"),
+			expectedCode: http.StatusInternalServerError,
+			respError:    "internal error",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			verifierMock := mocks.NewMockTokenVerifier(t)
+			if tc.header != "" {
+				verifierMock.On("Verify", mock.Anything, "token").Return(tc.claims, tc.verifyError).Once()
+			}
+
+			usersMock := mocks.NewMockUserResolver(t)
+			if tc.claims.String("sub") != "" {
+				usersMock.On("GetUser", mock.Anything, "alice").Return(storage.User{ID: 3, Name: "alice"}, tc.getError).Once()
+			}
+			if tc.create {
+				usersMock.On("CreateUser", mock.Anything, "alice").Return(storage.User{ID: 3, Name: "alice"}, tc.createError).Once()
+			}
+
+			var got auth.Principal
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = auth.PrincipalFrom(r.Context())
+			})
+			handler := mwauth.NewJWT(sldiscard.NewDiscardLogger(), verifierMock, usersMock, cfg)(next)
+
+			req, err := http.NewRequest(http.MethodGet, "/api/url", nil)
+			require.NoError(t, err)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.respError != "" {
+				var resp response.Message
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tc.respError, resp.Error)
+				return
+			}
+			require.Equal(t, tc.principal, got)
+		})
+	}
+}
+
+func TestJWTMiddleware_CachesUsers(t *testing.T) {
+	verifierMock := mocks.NewMockTokenVerifier(t)
+	verifierMock.On("Verify", mock.Anything, "token").Return(jwt.Claims{"sub": "alice"}, nil).Twice()
+
+	usersMock := mocks.NewMockUserResolver(t)
+	usersMock.On("GetUser", mock.Anything, "alice").Return(storage.User{ID: 3, Name: "alice"}, nil).Once()
+
+	cfg := &config.JWTConfig{UserClaim: "sub", ScopesClaim: "scope"}
+	handler := mwauth.NewJWT(sldiscard.NewDiscardLogger(), verifierMock, usersMock, cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for range 2 {
+		req, err := http.NewRequest(http.MethodGet, "/api/url", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+}
@@ -0,0 +1,37 @@
+package mwauth
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+)
+
+// Require returns a middleware that lets a request through only if its
+// principal was granted scope or is an admin. It must run after the
+// middleware returned by New.
+func Require(log *slog.Logger, scope string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/auth"),
+		)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ := auth.PrincipalFrom(r.Context())
+			if !principal.Allows(scope) {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="goshort", error="insufficient_scope", scope=%q`, scope))
+				sl.WriteResponse(log, w, r, http.StatusForbidden,
+					response.Error("missing scope "+scope),
+					"request not authorized",
+					slog.String("principal", principal.Name), slog.String("scope", scope))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mwratelimit_mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockLimiter creates a new instance of MockLimiter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLimiter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLimiter {
+	mock := &MockLimiter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockLimiter is an autogenerated mock type for the Limiter type
+type MockLimiter struct {
+	mock.Mock
+}
+
+type MockLimiter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLimiter) EXPECT() *MockLimiter_Expecter {
+	return &MockLimiter_Expecter{mock: &_m.Mock}
+}
+
+// Allow provides a mock function for the type MockLimiter
+func (_mock *MockLimiter) Allow(ctx context.Context, key string, limit config.RateLimit) (bool, time.Duration, error) {
+	ret := _mock.Called(ctx, key, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Allow")
+	}
+
+	var r0 bool
+	var r1 time.Duration
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, config.RateLimit) (bool, time.Duration, error)); ok {
+		return returnFunc(ctx, key, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, config.RateLimit) bool); ok {
+		r0 = returnFunc(ctx, key, limit)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, config.RateLimit) time.Duration); ok {
+		r1 = returnFunc(ctx, key, limit)
+	} else {
+		r1 = ret.Get(1).(time.Duration)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, config.RateLimit) error); ok {
+		r2 = returnFunc(ctx, key, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockLimiter_Allow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Allow'
+type MockLimiter_Allow_Call struct {
+	*mock.Call
+}
+
+// Allow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - limit config.RateLimit
+func (_e *MockLimiter_Expecter) Allow(ctx interface{}, key interface{}, limit interface{}) *MockLimiter_Allow_Call {
+	return &MockLimiter_Allow_Call{Call: _e.mock.On("Allow", ctx, key, limit)}
+}
+
+func (_c *MockLimiter_Allow_Call) Run(run func(ctx context.Context, key string, limit config.RateLimit)) *MockLimiter_Allow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 config.RateLimit
+		if args[2] != nil {
+			arg2 = args[2].(config.RateLimit)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLimiter_Allow_Call) Return(b bool, duration time.Duration, err error) *MockLimiter_Allow_Call {
+	_c.Call.Return(b, duration, err)
+	return _c
+}
+
+func (_c *MockLimiter_Allow_Call) RunAndReturn(run func(ctx context.Context, key string, limit config.RateLimit) (bool, time.Duration, error)) *MockLimiter_Allow_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,140 @@
+package mwratelimit
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/clientip"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+)
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit config.RateLimit) (bool, time.Duration, error)
+}
+
+// Limits applies the configured rate limits to requests. Each client gets
+// a token bucket per limit, keyed by its principal when the request is
+// authenticated and by its IP otherwise. A nil Limits limits nothing.
+type Limits struct {
+	log     *slog.Logger
+	limiter Limiter
+	cfg     *config.RateLimitConfig
+	ips     *clientip.Resolver
+}
+
+// New returns the limits of cfg. Anonymous clients are told apart by the
+// address ips resolves for them.
+func New(log *slog.Logger, limiter Limiter, cfg *config.RateLimitConfig, ips *clientip.Resolver) *Limits {
+	return &Limits{
+		log:     log.With(slog.String("component", "middleware/ratelimit")),
+		limiter: limiter,
+		cfg:     cfg,
+		ips:     ips,
+	}
+}
+
+// Create limits requests creating links.
+func (l *Limits) Create() func(next http.Handler) http.Handler {
+	if l == nil {
+		return passThrough
+	}
+	return l.middleware("create", l.cfg.Create)
+}
+
+// Delete limits requests deleting links.
+func (l *Limits) Delete() func(next http.Handler) http.Handler {
+	if l == nil {
+		return passThrough
+	}
+	return l.middleware("delete", l.cfg.Delete)
+}
+
+// Bulk limits requests creating many links at once, i.e. batch saves and
+// imports.
+func (l *Limits) Bulk() func(next http.Handler) http.Handler {
+	if l == nil {
+		return passThrough
+	}
+	return l.middleware("bulk", l.cfg.Bulk)
+}
+
+// Redirect limits redirects.
+func (l *Limits) Redirect() func(next http.Handler) http.Handler {
+	if l == nil {
+		return passThrough
+	}
+	return l.middleware("redirect", l.cfg.Redirect)
+}
+
+// Close releases the limiter, e.g. its Redis connection. Closing a nil
+// Limits is a no-op.
+func (l *Limits) Close(_ context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if closer, ok := l.limiter.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func passThrough(next http.Handler) http.Handler {
+	return next
+}
+
+// middleware returns a middleware taking a token from the bucket of the
+// client under name, or answering 429 with a Retry-After header when the
+// bucket is empty. Requests are let through when the limiter fails.
+func (l *Limits) middleware(name string, limit config.RateLimit) func(next http.Handler) http.Handler {
+	if limit.Rate <= 0 {
+		return passThrough
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := l.client(r)
+
+			allowed, retryAfter, err := l.limiter.Allow(r.Context(), name+":"+client, limit)
+			if err != nil {
+				l.log.Error("failed to check rate limit", slog.String("limit", name), slog.String("client", client), sl.Err(err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !allowed {
+				seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				sl.WriteResponse(l.log, w, r, http.StatusTooManyRequests,
+					response.Error("rate limit exceeded"),
+					"request rate limited", slog.String("limit", name), slog.String("client", client))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// client names the bucket owner of a request: its user, or for requests
+// that are not authenticated, its IP.
+func (l *Limits) client(r *http.Request) string {
+	if principal, ok := auth.PrincipalFrom(r.Context()); ok {
+		if principal.UserID != 0 {
+			return "user:" + strconv.FormatInt(principal.UserID, 10)
+		}
+		if principal.Name != "" {
+			return "name:" + principal.Name
+		}
+	}
+
+	return "ip:" + l.ips.IP(r)
+}
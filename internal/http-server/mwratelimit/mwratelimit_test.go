@@ -0,0 +1,168 @@
+package mwratelimit_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/clientip"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/mwratelimit"
+	mocks "github.com/n0f4ph4mst3r/goshort/internal/http-server/mwratelimit/mocks"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/response"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limit := config.RateLimit{Rate: 10, Period: time.Minute}
+	ips, err := clientip.New([]string{"10.0.0.0/8", "::1"})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name         string
+		principal    *auth.Principal
+		remoteAddr   string
+		headers      map[string]string
+		key          string
+		allowed      bool
+		retryAfter   time.Duration
+		limitError   error
+		expectedCode int
+		expectedWait string
+	}{
+		{
+			name:         "Allowed user",
+			principal:    &auth.Principal{UserID: 3, Name: "alice"},
+			key:          "create:user:3",
+			allowed:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Basic pair",
+			principal:    &auth.Principal{Name: "admin"},
+			key:          "create:name:admin",
+			allowed:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Limited user",
+			principal:    &auth.Principal{UserID: 3, Name: "alice"},
+			key:          "create:user:3",
+			retryAfter:   1500 * time.Millisecond,
+			expectedCode: http.StatusTooManyRequests,
+			expectedWait: "2",
+		},
+		{
+			name:         "Retry-After is at least a second",
+			remoteAddr:   "203.0.113.5:4321",
+			key:          "create:ip:203.0.113.5",
+			retryAfter:   10 * time.Millisecond,
+			expectedCode: http.StatusTooManyRequests,
+			expectedWait: "1",
+		},
+		{
+			name:         "Untrusted forwarding header is ignored",
+			remoteAddr:   "203.0.113.5:4321",
+			headers:      map[string]string{"X-Forwarded-For": "198.51.100.7"},
+			key:          "create:ip:203.0.113.5",
+			allowed:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Trusted proxy",
+			remoteAddr:   "10.0.0.2:4321",
+			headers:      map[string]string{"X-Forwarded-For": "192.0.2.1, 198.51.100.7, 10.0.0.1"},
+			key:          "create:ip:198.51.100.7",
+			allowed:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Only trusted hops",
+			remoteAddr:   "10.0.0.2:4321",
+			headers:      map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.1"},
+			key:          "create:ip:10.0.0.3",
+			allowed:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Trusted proxy with X-Real-IP",
+			remoteAddr:   "[::1]:4321",
+			headers:      map[string]string{"X-Real-IP": "2001:db8::7"},
+			key:          "create:ip:2001:db8::7",
+			allowed:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Limiter failure lets the request through",
+			principal:    &auth.Principal{UserID: 3, Name: "alice"},
+			key:          "create:user:3",
+			limitError:   errors.New("connection reset"),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			limiterMock := mocks.NewMockLimiter(t)
+			limiterMock.On("Allow", mock.Anything, tc.key, limit).Return(tc.allowed, tc.retryAfter, tc.limitError).Once()
+
+			limits := mwratelimit.New(sldiscard.NewDiscardLogger(), limiterMock, &config.RateLimitConfig{Create: limit}, ips)
+			handler := limits.Create()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req, err := http.NewRequest(http.MethodPost, "/api/url", nil)
+			require.NoError(t, err)
+			if tc.remoteAddr != "" {
+				req.RemoteAddr = tc.remoteAddr
+			}
+			for name, value := range tc.headers {
+				req.Header.Set(name, value)
+			}
+			if tc.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *tc.principal))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusTooManyRequests {
+				require.Equal(t, tc.expectedWait, rr.Header().Get("Retry-After"))
+
+				var resp response.Message
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, "rate limit exceeded", resp.Error)
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware_Disabled(t *testing.T) {
+	limits := mwratelimit.New(sldiscard.NewDiscardLogger(), mocks.NewMockLimiter(t), &config.RateLimitConfig{
+		Create: config.RateLimit{Rate: 10, Period: time.Minute},
+	}, nil)
+
+	var nilLimits *mwratelimit.Limits
+	for _, middleware := range []func(http.Handler) http.Handler{limits.Delete(), limits.Redirect(), limits.Bulk(), nilLimits.Create()} {
+		req, err := http.NewRequest(http.MethodGet, "/api/url/abc", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		middleware(http.NotFoundHandler()).ServeHTTP(rr, req)
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	}
+
+	require.NoError(t, nilLimits.Close(context.Background()))
+	require.NoError(t, limits.Close(context.Background()))
+}
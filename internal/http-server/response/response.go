@@ -2,6 +2,7 @@ package response
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -39,6 +40,18 @@ func ValidationError(errs validator.ValidationErrors) Message {
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is a required field", err.Field()))
 		case "url":
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not a valid URL", err.Field()))
+		case "min":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be at least %s%s", err.Field(), err.Param(), unit(err)))
+		case "max":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be at most %s%s", err.Field(), err.Param(), unit(err)))
+		case "alias_charset":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s contains characters that are not allowed", err.Field()))
+		case "alias_reserved":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s is a reserved word", err.Field()))
+		case "alias_blocked":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s contains a blocked word", err.Field()))
+		case "excluded_with":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s cannot be used together with %s", err.Field(), strings.ReplaceAll(err.Param(), " ", " or ")))
 		default:
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not valid", err.Field()))
 		}
@@ -49,3 +62,11 @@ func ValidationError(errs validator.ValidationErrors) Message {
 		Error:  strings.Join(errMsgs, ", "),
 	}
 }
+
+// unit names what a min/max bound counts for string fields.
+func unit(err validator.FieldError) string {
+	if err.Kind() == reflect.String {
+		return " characters long"
+	}
+	return ""
+}
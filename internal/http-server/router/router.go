@@ -0,0 +1,141 @@
+package router
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/alias"
+	"github.com/n0f4ph4mst3r/goshort/internal/analytics"
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/destination"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/apikey"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/backup"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/erase"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/history"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/list"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/redirect"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/save"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/stats"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/suggest"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/update"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/mwauth"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/mwlogger"
+	"github.com/n0f4ph4mst3r/goshort/internal/http-server/mwratelimit"
+	"github.com/n0f4ph4mst3r/goshort/internal/jwt"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	"github.com/n0f4ph4mst3r/goshort/internal/transfer"
+	"github.com/n0f4ph4mst3r/goshort/internal/urlnorm"
+)
+
+// Deps are the services the routes are served by. Recorder, Blocklist,
+// Tokens and Limits may be nil when their feature is off.
+type Deps struct {
+	Links        *storage.UrlStorage
+	Keys         storage.KeyService
+	Recorder     *analytics.Recorder
+	Policy       *alias.Policy
+	Destinations *destination.Policy
+	Blocklist    *destination.Blocklist
+	Tokens       *jwt.Verifier
+	Limits       *mwratelimit.Limits
+}
+
+func New(log *slog.Logger, cfg *config.Config, deps Deps) http.Handler {
+	url_storage, keys, policy, blocklist, limits := deps.Links, deps.Keys, deps.Policy, deps.Blocklist, deps.Limits
+
+	router := chi.NewRouter()
+	router.Use(middleware.RequestID)
+	router.Use(middleware.Logger)
+	router.Use(mwlogger.New(log))
+	router.Use(middleware.Recoverer)
+	router.Use(middleware.URLFormat)
+
+	var gen save.AliasGenerator
+	switch {
+	case cfg.Alias.Generator == config.AliasSequence:
+		gen = alias.NewSequence(url_storage, &cfg.Alias)
+	case cfg.Alias.CaseInsensitive:
+		// Seven characters of the smaller alphabet keep roughly the
+		// keyspace of six base62 characters.
+		gen = &save.DefaultRandomAlias{Alphabet: alias.Unambiguous, Length: 7}
+	}
+
+	canonicalizer := urlnorm.New(&cfg.URL)
+	checks := destination.Chain{deps.Destinations, blocklist}
+	links := transfer.New(url_storage, canonicalizer, checks)
+
+	authenticate := mwauth.New(log, keys, mwauth.Credentials{
+		User:     cfg.HTTPServer.User,
+		Password: cfg.HTTPServer.Password,
+	})
+	if cfg.Auth.Mode == config.AuthJWT {
+		authenticate = mwauth.NewJWT(log, deps.Tokens, keys, &cfg.Auth.JWT)
+	}
+	canRead := mwauth.Require(log, auth.ScopeLinksRead)
+	canWrite := mwauth.Require(log, auth.ScopeLinksWrite)
+	canDelete := mwauth.Require(log, auth.ScopeLinksDelete)
+	isAdmin := mwauth.Require(log, auth.ScopeAdmin)
+
+	limitCreate := limits.Create()
+	limitBulk := limits.Bulk()
+	limitDelete := limits.Delete()
+
+	router.Route("/api", func(api_routes chi.Router) {
+		api_routes.With(limits.Redirect()).Get("/url/{alias}", redirect.New(log, url_storage, deps.Recorder, blocklist, url_storage))
+
+		api_routes.Route("/url", func(auth_routes chi.Router) {
+			auth_routes.Use(authenticate)
+
+			auth_routes.With(canRead).Get("/", list.New(log, url_storage))
+			auth_routes.With(canWrite, limitCreate).Post("/", save.New(log, url_storage, gen, policy, canonicalizer, checks))
+			auth_routes.With(canWrite, limitCreate, limitBulk).Post("/batch", save.NewBatch(log, url_storage, gen, policy, canonicalizer, checks))
+			auth_routes.With(canDelete, limitDelete).Delete("/", erase.NewByOrigin(log, url_storage, canonicalizer))
+			auth_routes.With(canDelete, limitDelete).Delete("/{alias}", erase.New(log, url_storage))
+			auth_routes.With(canWrite).Patch("/{alias}", update.New(log, url_storage, canonicalizer, checks))
+			auth_routes.With(canRead).Get("/{alias}/stats", stats.New(log, url_storage))
+			auth_routes.With(canRead).Get("/{alias}/history", history.New(log, url_storage))
+			auth_routes.With(canWrite).Post("/{alias}/rollback", history.NewRollback(log, url_storage, checks))
+			auth_routes.With(canDelete).Post("/{alias}/restore", erase.NewRestore(log, url_storage))
+		})
+
+		api_routes.Route("/trash", func(auth_routes chi.Router) {
+			auth_routes.Use(authenticate)
+
+			auth_routes.With(canRead).Get("/", list.NewTrash(log, url_storage))
+		})
+
+		api_routes.Route("/alias", func(auth_routes chi.Router) {
+			auth_routes.Use(authenticate)
+
+			auth_routes.With(canWrite).Get("/suggest", suggest.New(log, url_storage, policy))
+		})
+
+		api_routes.Route("/backup", func(auth_routes chi.Router) {
+			auth_routes.Use(authenticate)
+
+			auth_routes.With(canRead).Get("/export", backup.NewExport(log, links))
+			auth_routes.With(canWrite, limitCreate, limitBulk).Post("/import", backup.NewImport(log, links, &cfg.Import))
+		})
+
+		api_routes.Route("/users", func(auth_routes chi.Router) {
+			auth_routes.Use(authenticate, isAdmin)
+
+			auth_routes.Get("/", apikey.NewListUsers(log, keys))
+			auth_routes.Post("/", apikey.NewCreateUser(log, keys))
+			auth_routes.Get("/{user}/keys", apikey.NewListKeys(log, keys))
+			auth_routes.Post("/{user}/keys", apikey.NewIssue(log, keys))
+		})
+
+		api_routes.Route("/keys", func(auth_routes chi.Router) {
+			auth_routes.Use(authenticate, isAdmin)
+
+			auth_routes.Delete("/{id}", apikey.NewRevoke(log, keys))
+		})
+	})
+
+	return router
+}
@@ -0,0 +1,195 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+)
+
+const (
+	algRS256 = "RS256"
+	algES256 = "ES256"
+)
+
+var (
+	ErrMalformed     = errors.New("malformed token")
+	ErrAlgorithm     = errors.New("unsupported signing algorithm")
+	ErrUnknownKey    = errors.New("unknown signing key")
+	ErrSignature     = errors.New("invalid token signature")
+	ErrExpired       = errors.New("token has expired")
+	ErrNotYetValid   = errors.New("token is not valid yet")
+	ErrIssuer        = errors.New("unexpected token issuer")
+	ErrAudience      = errors.New("unexpected token audience")
+	ErrMissingClaims = errors.New("token lacks a required claim")
+)
+
+// Keys looks up the public key a token was signed with.
+type Keys interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, bool)
+}
+
+// Claims is the payload of a verified token.
+type Claims map[string]any
+
+// String returns a string claim, or "" if it is missing or not a string.
+func (c Claims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Strings returns a claim holding either a space-separated string, as the
+// OAuth scope claim does, or an array of strings.
+func (c Claims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func (c Claims) time(name string) (time.Time, bool) {
+	n, ok := c[name].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	sec, frac := int64(f), f-float64(int64(f))
+	return time.Unix(sec, int64(frac*float64(time.Second))), true
+}
+
+// Verifier checks RS256 and ES256 signed JWTs against a key set and
+// validates their time, issuer and audience claims.
+type Verifier struct {
+	keys Keys
+	cfg  *config.JWTConfig
+	now  func() time.Time
+}
+
+func NewVerifier(keys Keys, cfg *config.JWTConfig) *Verifier {
+	return &Verifier{keys: keys, cfg: cfg, now: time.Now}
+}
+
+// Verify returns the claims of a valid token. Tokens must carry an exp
+// claim and, if configured, the expected issuer and audience; exp and nbf
+// are checked with the configured leeway.
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Alg != algRS256 && header.Alg != algES256 {
+		return nil, fmt.Errorf("%w %q", ErrAlgorithm, header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	key, ok := v.keys.Key(ctx, header.Kid)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, header.Kid)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !verifySignature(header.Alg, key, digest[:], sig) {
+		return nil, ErrSignature
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) validate(claims Claims) error {
+	now := v.now()
+
+	exp, ok := claims.time("exp")
+	if !ok {
+		return fmt.Errorf("%w: exp", ErrMissingClaims)
+	}
+	if !now.Before(exp.Add(v.cfg.Leeway)) {
+		return ErrExpired
+	}
+	if nbf, ok := claims.time("nbf"); ok && now.Add(v.cfg.Leeway).Before(nbf) {
+		return ErrNotYetValid
+	}
+
+	if v.cfg.Issuer != "" && claims.String("iss") != v.cfg.Issuer {
+		return ErrIssuer
+	}
+	if v.cfg.Audience != "" && !slices.Contains(claims.Strings("aud"), v.cfg.Audience) {
+		return ErrAudience
+	}
+
+	return nil
+}
+
+// verifySignature checks sig over digest. The key type must match the
+// algorithm, so that a token cannot pick how its key is used.
+func verifySignature(alg string, key crypto.PublicKey, digest, sig []byte) bool {
+	switch alg {
+	case algRS256:
+		pub, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest, sig) == nil
+	case algES256:
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(sig) != 64 {
+			return false
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(pub, digest, r, s)
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformed
+	}
+
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return ErrMalformed
+	}
+
+	return nil
+}
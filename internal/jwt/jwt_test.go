@@ -0,0 +1,179 @@
+package jwt_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/jwt"
+	"github.com/n0f4ph4mst3r/goshort/internal/jwt/jwttest"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+)
+
+func writeJWKS(t *testing.T, path string, signers ...*jwttest.Signer) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, jwttest.JWKS(signers...), 0o600))
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey := jwttest.NewRS256("rsa-1")
+	ecKey := jwttest.NewES256("ec-1")
+	stranger := jwttest.NewRS256("rsa-1")
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, rsaKey, ecKey)
+
+	cfg := &config.JWTConfig{JWKSFile: path, Issuer: "https://id.example", Audience: "goshort", Leeway: time.Minute}
+	keys, err := jwt.NewKeySet(sldiscard.NewDiscardLogger(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, keys.Close(context.Background())) })
+	verifier := jwt.NewVerifier(keys, cfg)
+
+	now := time.Now()
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"iss":   "https://id.example",
+			"aud":   "goshort",
+			"sub":   "alice",
+			"scope": "links:read links:write",
+			"exp":   now.Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	cases := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{name: "RS256", token: rsaKey.Sign(claims(nil))},
+		{name: "ES256", token: ecKey.Sign(claims(nil))},
+		{name: "Audience array", token: rsaKey.Sign(claims(map[string]any{"aud": []string{"other", "goshort"}}))},
+		{name: "Expired within leeway", token: rsaKey.Sign(claims(map[string]any{"exp": now.Add(-30 * time.Second).Unix()}))},
+		{name: "Expired", token: rsaKey.Sign(claims(map[string]any{"exp": now.Add(-2 * time.Minute).Unix()})), err: jwt.ErrExpired},
+		{name: "Missing exp", token: rsaKey.Sign(claims(map[string]any{"exp": nil})), err: jwt.ErrMissingClaims},
+		{name: "Not yet valid", token: rsaKey.Sign(claims(map[string]any{"nbf": now.Add(time.Hour).Unix()})), err: jwt.ErrNotYetValid},
+		{name: "Wrong issuer", token: rsaKey.Sign(claims(map[string]any{"iss": "https://evil.example"})), err: jwt.ErrIssuer},
+		{name: "Wrong audience", token: ecKey.Sign(claims(map[string]any{"aud": "other"})), err: jwt.ErrAudience},
+		{name: "Foreign key", token: stranger.Sign(claims(nil)), err: jwt.ErrSignature},
+		{name: "Unknown kid", token: jwttest.NewES256("ec-2").Sign(claims(nil)), err: jwt.ErrUnknownKey},
+		{name: "Unsigned", token: rsaKey.SignWithHeader(map[string]any{"alg": "none", "kid": "rsa-1"}, claims(nil)), err: jwt.ErrAlgorithm},
+		{name: "HMAC", token: rsaKey.SignWithHeader(map[string]any{"alg": "HS256", "kid": "rsa-1"}, claims(nil)), err: jwt.ErrAlgorithm},
+		{name: "Algorithm mismatch", token: rsaKey.SignWithHeader(map[string]any{"alg": "ES256", "kid": "rsa-1"}, claims(nil)), err: jwt.ErrSignature},
+		{name: "Malformed", token: "not.a-token", err: jwt.ErrMalformed},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := verifier.Verify(context.Background(), tc.token)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "alice", got.String("sub"))
+			require.Equal(t, []string{"links:read", "links:write"}, got.Strings("scope"))
+		})
+	}
+}
+
+func TestVerify_TamperedPayload(t *testing.T) {
+	signer := jwttest.NewES256("ec-1")
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, signer)
+
+	cfg := &config.JWTConfig{JWKSFile: path}
+	keys, err := jwt.NewKeySet(sldiscard.NewDiscardLogger(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, keys.Close(context.Background())) })
+
+	exp := time.Now().Add(time.Hour).Unix()
+	token := signer.Sign(map[string]any{"sub": "alice", "exp": exp})
+	forged := signer.Sign(map[string]any{"sub": "admin", "exp": exp})
+
+	a, b := strings.Split(token, "."), strings.Split(forged, ".")
+
+	_, err = jwt.NewVerifier(keys, cfg).Verify(context.Background(), a[0]+"."+b[1]+"."+a[2])
+	require.ErrorIs(t, err, jwt.ErrSignature)
+}
+
+func TestKeySet_Refresh(t *testing.T) {
+	first := jwttest.NewRS256("k1")
+	second := jwttest.NewES256("k2")
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, first)
+
+	keys, err := jwt.NewKeySet(sldiscard.NewDiscardLogger(), &config.JWTConfig{JWKSFile: path, RefreshInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, keys.Close(context.Background())) })
+
+	_, ok := keys.Key(context.Background(), "k2")
+	require.False(t, ok)
+
+	writeJWKS(t, path, second)
+
+	require.Eventually(t, func() bool {
+		_, ok := keys.Key(context.Background(), "k2")
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	time.Sleep(50 * time.Millisecond)
+
+	_, ok = keys.Key(context.Background(), "k2")
+	require.True(t, ok, "keys loaded before must survive a failed refresh")
+}
+
+func TestKeySet_URL(t *testing.T) {
+	signer := jwttest.NewES256("")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jwttest.JWKS(signer))
+	}))
+	t.Cleanup(srv.Close)
+
+	keys, err := jwt.NewKeySet(sldiscard.NewDiscardLogger(), &config.JWTConfig{JWKSURL: srv.URL})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, keys.Close(context.Background())) })
+
+	_, ok := keys.Key(context.Background(), "")
+	require.True(t, ok)
+}
+
+func TestNewKeySet_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	log := sldiscard.NewDiscardLogger()
+
+	_, err := jwt.NewKeySet(log, &config.JWTConfig{})
+	require.Error(t, err)
+
+	empty := filepath.Join(dir, "empty.json")
+	require.NoError(t, os.WriteFile(empty, []byte(`{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`), 0o600))
+	_, err = jwt.NewKeySet(log, &config.JWTConfig{JWKSFile: empty})
+	require.ErrorIs(t, err, jwt.ErrNoKeys)
+
+	weak := filepath.Join(dir, "weak.json")
+	require.NoError(t, os.WriteFile(weak, []byte(`{"keys": [{"kty": "RSA", "kid": "w", "n": "AQAB", "e": "AQAB"}]}`), 0o600))
+	_, err = jwt.NewKeySet(log, &config.JWTConfig{JWKSFile: weak})
+	require.ErrorContains(t, err, "modulus shorter")
+}
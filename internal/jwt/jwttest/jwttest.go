@@ -0,0 +1,114 @@
+package jwttest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+)
+
+// Signer signs tokens with a freshly generated key, for tests.
+type Signer struct {
+	Kid string
+	Alg string
+	key crypto.Signer
+}
+
+func NewRS256(kid string) *Signer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return &Signer{Kid: kid, Alg: "RS256", key: key}
+}
+
+func NewES256(kid string) *Signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return &Signer{Kid: kid, Alg: "ES256", key: key}
+}
+
+// JWK returns the public key of s as a JSON Web Key.
+func (s *Signer) JWK() map[string]any {
+	enc := base64.RawURLEncoding.EncodeToString
+
+	switch pub := s.key.Public().(type) {
+	case *rsa.PublicKey:
+		return map[string]any{
+			"kty": "RSA", "kid": s.Kid, "use": "sig", "alg": s.Alg,
+			"n": enc(pub.N.Bytes()), "e": enc(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		point, err := pub.Bytes()
+		if err != nil {
+			panic(err)
+		}
+		return map[string]any{
+			"kty": "EC", "kid": s.Kid, "use": "sig", "alg": s.Alg, "crv": "P-256",
+			"x": enc(point[1:33]), "y": enc(point[33:]),
+		}
+	}
+	panic("unsupported key type")
+}
+
+// JWKS returns a JWKS document holding the public keys of signers.
+func JWKS(signers ...*Signer) []byte {
+	keys := make([]map[string]any, len(signers))
+	for i, s := range signers {
+		keys[i] = s.JWK()
+	}
+
+	raw, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		panic(err)
+	}
+	return raw
+}
+
+// Sign returns a token carrying claims, with the algorithm and key id of s
+// in its header.
+func (s *Signer) Sign(claims map[string]any) string {
+	return s.SignWithHeader(map[string]any{"alg": s.Alg, "kid": s.Kid, "typ": "JWT"}, claims)
+}
+
+// SignWithHeader signs claims under an arbitrary header, e.g. one naming a
+// different algorithm than the key uses.
+func (s *Signer) SignWithHeader(header, claims map[string]any) string {
+	input := segment(header) + "." + segment(claims)
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch key := s.key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			panic(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, ss, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			panic(err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		ss.FillBytes(sig[32:])
+	}
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func segment(v any) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
@@ -0,0 +1,290 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+)
+
+const (
+	// minRefetch limits how often an unknown key id triggers a reload.
+	minRefetch = time.Minute
+	// maxJWKSSize bounds the size of a JWKS document fetched from a URL.
+	maxJWKSSize = 1 << 20
+	minRSABits  = 2048
+)
+
+var ErrNoKeys = errors.New("JWKS contains no usable keys")
+
+// KeySet holds the public keys of a JWKS read from a local file or a URL.
+// It is re-read on every refresh interval until closed, and when a token
+// names a key that is not in the set; if a reload fails, the keys loaded
+// before stay in use. Only RSA and P-256 signing keys are kept.
+type KeySet struct {
+	log    *slog.Logger
+	cfg    *config.JWTConfig
+	client *http.Client
+
+	mu       sync.RWMutex
+	keys     map[string]crypto.PublicKey
+	loadedAt time.Time
+	reload   sync.Mutex
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewKeySet loads the JWKS named in cfg and starts refreshing it.
+func NewKeySet(log *slog.Logger, cfg *config.JWTConfig) (*KeySet, error) {
+	const op = "jwt.NewKeySet"
+
+	if (cfg.JWKSFile == "") == (cfg.JWKSURL == "") {
+		return nil, fmt.Errorf("%s: exactly one of jwks_file and jwks_url must be set", op)
+	}
+
+	k := &KeySet{
+		log:    log.With(slog.String("component", "jwt/keyset")),
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	if err := k.load(context.Background()); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	go k.run()
+
+	return k, nil
+}
+
+// Key returns the key with the given id. An empty id matches the only key
+// of the set. Unknown ids reload the set, at most once per minRefetch.
+func (k *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, bool) {
+	if key, ok := k.lookup(kid); ok {
+		return key, true
+	}
+
+	k.reload.Lock()
+	defer k.reload.Unlock()
+
+	k.mu.RLock()
+	stale := time.Since(k.loadedAt) >= minRefetch
+	k.mu.RUnlock()
+
+	if stale {
+		if err := k.load(ctx); err != nil {
+			k.log.Error("failed to reload JWKS", slog.String("kid", kid), sl.Err(err))
+		}
+	}
+
+	return k.lookup(kid)
+}
+
+func (k *KeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// Close stops refreshing, or gives up when ctx is done.
+func (k *KeySet) Close(ctx context.Context) error {
+	if k == nil {
+		return nil
+	}
+
+	k.once.Do(func() { close(k.stop) })
+
+	select {
+	case <-k.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (k *KeySet) run() {
+	defer close(k.done)
+
+	if k.cfg.RefreshInterval <= 0 {
+		<-k.stop
+		return
+	}
+
+	ticker := time.NewTicker(k.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := k.load(context.Background()); err != nil {
+				k.log.Error("failed to refresh JWKS", sl.Err(err))
+			}
+		case <-k.stop:
+			return
+		}
+	}
+}
+
+func (k *KeySet) load(ctx context.Context) error {
+	raw, err := k.read(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys, err := parseJWKS(raw)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.keys, k.loadedAt = keys, time.Now()
+	k.mu.Unlock()
+
+	k.log.Info("JWKS loaded", slog.Int("keys", len(keys)))
+
+	return nil
+}
+
+func (k *KeySet) read(ctx context.Context) ([]byte, error) {
+	if k.cfg.JWKSFile != "" {
+		return os.ReadFile(k.cfg.JWKSFile)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.cfg.JWKSURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", k.cfg.JWKSURL, resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS returns the signing keys of a JWKS document by key id. Keys of
+// other types, curves or uses are skipped; malformed ones are an error.
+func parseJWKS(raw []byte) (map[string]crypto.PublicKey, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for i, j := range doc.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch {
+		case j.Kty == "RSA" && (j.Alg == "" || j.Alg == algRS256):
+			key, err = rsaKey(j)
+		case j.Kty == "EC" && j.Crv == "P-256" && (j.Alg == "" || j.Alg == algES256):
+			key, err = ecKey(j)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %d (kid %q): %w", i, j.Kid, err)
+		}
+
+		keys[j.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	return keys, nil
+}
+
+func rsaKey(j jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(j.N)
+	if err != nil {
+		return nil, fmt.Errorf("modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(j.E)
+	if err != nil {
+		return nil, fmt.Errorf("exponent: %w", err)
+	}
+
+	exp := new(big.Int).SetBytes(e)
+	if !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+		return nil, errors.New("exponent out of range")
+	}
+
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}
+	if key.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("modulus shorter than %d bits", minRSABits)
+	}
+
+	return key, nil
+}
+
+func ecKey(j jwk) (*ecdsa.PublicKey, error) {
+	x, err := base64.RawURLEncoding.DecodeString(j.X)
+	if err != nil {
+		return nil, fmt.Errorf("x: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(j.Y)
+	if err != nil {
+		return nil, fmt.Errorf("y: %w", err)
+	}
+	if len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("coordinates must be 32 bytes long")
+	}
+
+	point := append(append([]byte{4}, x...), y...)
+	return ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+}
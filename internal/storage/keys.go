@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type User struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// APIKey is a credential issued to a user. Only the SHA-256 hash of the
+// key is stored; Prefix keeps its first characters so that users can tell
+// their keys apart.
+type APIKey struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	UserName   string     `json:"user,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Prefix     string     `json:"prefix"`
+	Hash       string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// KeyService stores users and their API keys.
+type KeyService interface {
+	CreateUser(ctx context.Context, name string) (User, error)
+	GetUser(ctx context.Context, name string) (User, error)
+	ListUsers(ctx context.Context) ([]User, error)
+	CreateAPIKey(ctx context.Context, key APIKey) (APIKey, error)
+	ListAPIKeys(ctx context.Context, userID int64) ([]APIKey, error)
+	// FindAPIKey looks a key up by its hash, revoked or not, and fills in
+	// the name of its user.
+	FindAPIKey(ctx context.Context, hash string) (APIKey, error)
+	TouchAPIKey(ctx context.Context, id int64, at time.Time) error
+	RevokeAPIKey(ctx context.Context, id int64) error
+}
+
+// Backend is implemented by the storage backends, which keep links as
+// well as the users allowed to manage them.
+type Backend interface {
+	UrlService
+	KeyService
+}
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrUserExists   = errors.New("user already exists")
+	ErrKeyNotFound  = errors.New("API key not found")
+)
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SortOrder string
+
+const (
+	OrderAsc  SortOrder = "asc"
+	OrderDesc SortOrder = "desc"
+)
+
+// Cursor points at the last link of a page. Links are ordered by
+// (created_at, id), so the pair identifies a position even when several
+// links share a creation time.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	ts, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: n}, nil
+}
+
+type ListQuery struct {
+	Limit   int
+	After   *Cursor
+	Order   SortOrder
+	Search  string
+	Host    string
+	Trashed bool
+}
+
+type ListPage struct {
+	Links []Link
+	Next  *Cursor
+}
+
+// ListURLs returns one page of links. The service is asked for one extra
+// row to find out whether another page follows.
+func (s *UrlStorage) ListURLs(ctx context.Context, q ListQuery) (ListPage, error) {
+	if q.Order == "" {
+		q.Order = OrderDesc
+	}
+	q.Host = strings.ToLower(q.Host)
+
+	limit := q.Limit
+	q.Limit++
+
+	links, err := s.service.ListURLs(ctx, q)
+	if err != nil {
+		return ListPage{}, err
+	}
+
+	page := ListPage{Links: links}
+	if len(links) > limit {
+		page.Links = links[:limit]
+		last := page.Links[limit-1]
+		page.Next = &Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	if page.Links == nil {
+		page.Links = []Link{}
+	}
+
+	return page, nil
+}
+
+// HostOf returns the lower-cased host name of a URL, or an empty string if
+// it cannot be parsed. It is stored alongside the origin for host filtering.
+func HostOf(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// EscapeLike escapes the LIKE wildcards in s using a backslash, so that a
+// user-supplied search term is matched literally.
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Matches reports whether the link passes the trash, search and host
+// filters of q. Backends that filter in SQL do not need it.
+func (q ListQuery) Matches(link Link) bool {
+	if q.Trashed != (link.DeletedAt != nil) {
+		return false
+	}
+	if q.Search != "" && !strings.Contains(strings.ToLower(link.URL), strings.ToLower(q.Search)) {
+		return false
+	}
+	if q.Host != "" && HostOf(link.URL) != q.Host {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+var errCacheMiss = errors.New("cache miss")
+
+type entry struct {
+	value     string
+	expiresAt time.Time
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// Cache is an in-process replacement for the Redis cache. Entries expire
+// after the configured TTL; expired entries are dropped lazily on access
+// and swept on writes at most once per TTL.
+type Cache struct {
+	mu        sync.Mutex
+	entries   map[string]entry
+	cfg       *config.CacheConfig
+	lastSweep time.Time
+}
+
+func NewCache(cfg *config.CacheConfig) *Cache {
+	return &Cache{
+		entries:   make(map[string]entry),
+		cfg:       cfg,
+		lastSweep: time.Now(),
+	}
+}
+
+func (c *Cache) SetURL(_ context.Context, u, alias string, expiresAt *time.Time) error {
+	ttl, ok := storage.CacheTTL(c.cfg.TTL, expiresAt)
+	if !ok {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.sweep(now)
+
+	c.set(c.cfg.PrefixURL+alias, u, ttl, now)
+	if expiresAt == nil {
+		c.set(c.cfg.PrefixRev+u, alias, c.cfg.ReverseIndexTTL, now)
+	}
+
+	return nil
+}
+
+func (c *Cache) GetURL(_ context.Context, alias string) (string, error) {
+	const op = "storage.memory.Cache.GetURL"
+
+	u, ok := c.get(c.cfg.PrefixURL + alias)
+	if !ok {
+		return "", fmt.Errorf("%s: %w", op, errCacheMiss)
+	}
+
+	return u, nil
+}
+
+func (c *Cache) GetAlias(_ context.Context, u string) (string, error) {
+	const op = "storage.memory.Cache.GetAlias"
+
+	alias, ok := c.get(c.cfg.PrefixRev + u)
+	if !ok {
+		return "", fmt.Errorf("%s: %w", op, errCacheMiss)
+	}
+
+	return alias, nil
+}
+
+func (c *Cache) DelURL(_ context.Context, u, alias string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, c.cfg.PrefixURL+alias)
+	delete(c.entries, c.cfg.PrefixRev+u)
+
+	return nil
+}
+
+func (c *Cache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if e.expired(time.Now()) {
+		delete(c.entries, key)
+		return "", false
+	}
+
+	return e.value, true
+}
+
+func (c *Cache) set(key, value string, ttl time.Duration, now time.Time) {
+	e := entry{value: value}
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+	}
+	c.entries[key] = e
+}
+
+func (c *Cache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.cfg.TTL {
+		return
+	}
+
+	for key, e := range c.entries {
+		if e.expired(now) {
+			delete(c.entries, key)
+		}
+	}
+	c.lastSweep = now
+}
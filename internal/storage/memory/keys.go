@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+func (s *Storage) CreateUser(_ context.Context, name string) (storage.User, error) {
+	const op = "storage.memory.CreateUser"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.Name == name {
+			return storage.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+		}
+	}
+
+	user := storage.User{ID: int64(len(s.users) + 1), Name: name, CreatedAt: time.Now()}
+	s.users = append(s.users, user)
+
+	return user, nil
+}
+
+func (s *Storage) GetUser(_ context.Context, name string) (storage.User, error) {
+	const op = "storage.memory.GetUser"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if user.Name == name {
+			return user, nil
+		}
+	}
+
+	return storage.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+}
+
+func (s *Storage) ListUsers(_ context.Context) ([]storage.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]storage.User{}, s.users...), nil
+}
+
+func (s *Storage) CreateAPIKey(_ context.Context, key storage.APIKey) (storage.APIKey, error) {
+	const op = "storage.memory.CreateAPIKey"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key.UserID <= 0 || key.UserID > int64(len(s.users)) {
+		return storage.APIKey{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+
+	key.ID = int64(len(s.keys) + 1)
+	key.UserName = ""
+	s.keys = append(s.keys, key)
+
+	return key, nil
+}
+
+func (s *Storage) ListAPIKeys(_ context.Context, userID int64) ([]storage.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := []storage.APIKey{}
+	for _, key := range s.keys {
+		if key.UserID == userID {
+			keys = append(keys, s.withUser(key))
+		}
+	}
+
+	return keys, nil
+}
+
+func (s *Storage) FindAPIKey(_ context.Context, hash string) (storage.APIKey, error) {
+	const op = "storage.memory.FindAPIKey"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.keys {
+		if key.Hash == hash {
+			return s.withUser(key), nil
+		}
+	}
+
+	return storage.APIKey{}, fmt.Errorf("%s: %w", op, storage.ErrKeyNotFound)
+}
+
+func (s *Storage) TouchAPIKey(_ context.Context, id int64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id > 0 && id <= int64(len(s.keys)) {
+		s.keys[id-1].LastUsedAt = &at
+	}
+
+	return nil
+}
+
+func (s *Storage) RevokeAPIKey(_ context.Context, id int64) error {
+	const op = "storage.memory.RevokeAPIKey"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id <= 0 || id > int64(len(s.keys)) || s.keys[id-1].RevokedAt != nil {
+		return fmt.Errorf("%s: %w", op, storage.ErrKeyNotFound)
+	}
+	now := time.Now()
+	s.keys[id-1].RevokedAt = &now
+
+	return nil
+}
+
+// withUser fills in the user name the way the SQL backends join it.
+func (s *Storage) withUser(key storage.APIKey) storage.APIKey {
+	key.UserName = s.users[key.UserID-1].Name
+	return key
+}
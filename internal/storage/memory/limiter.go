@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+)
+
+// limiterSweepInterval is how often buckets that have filled up again are
+// dropped.
+const limiterSweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	fullAt time.Time
+}
+
+// Limiter is an in-process token bucket rate limiter, for single instance
+// deployments. Buckets are created on first use and swept once full again.
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets:   make(map[string]*bucket),
+		now:       time.Now,
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow takes a token from the bucket of key. If the bucket is empty, it
+// reports how long until the next token is added instead.
+func (l *Limiter) Allow(_ context.Context, key string, limit config.RateLimit) (bool, time.Duration, error) {
+	interval := max(limit.Period/time.Duration(limit.Rate), 1)
+	burst := float64(limit.Burst)
+	if limit.Burst <= 0 {
+		burst = float64(limit.Rate)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= limiterSweepInterval {
+		for k, b := range l.buckets {
+			if !now.Before(b.fullAt) {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(burst, b.tokens+float64(elapsed)/float64(interval))
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(interval)), nil
+	}
+
+	b.tokens--
+	b.fullAt = now.Add(time.Duration((burst - b.tokens) * float64(interval)))
+
+	return true, 0, nil
+}
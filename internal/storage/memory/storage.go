@@ -0,0 +1,437 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+type Storage struct {
+	mu      sync.RWMutex
+	links   map[string]storage.Link
+	clicks  []storage.Click
+	history map[string][]storage.Change
+	users   []storage.User
+	keys    []storage.APIKey
+	nextID  int64
+	seq     int64
+}
+
+func New() *Storage {
+	return &Storage{
+		links:   make(map[string]storage.Link),
+		history: make(map[string][]storage.Change),
+	}
+}
+
+func (s *Storage) SaveURL(_ context.Context, link storage.Link) error {
+	const op = "storage.memory.SaveURL"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.links[link.Alias]; ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrUrlExists)
+	}
+
+	s.nextID++
+	link.ID = s.nextID
+	s.links[link.Alias] = link
+
+	return nil
+}
+
+func (s *Storage) SaveURLs(_ context.Context, links []storage.Link) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var saved []string
+	for _, link := range links {
+		if _, ok := s.links[link.Alias]; ok {
+			continue
+		}
+
+		s.nextID++
+		link.ID = s.nextID
+		s.links[link.Alias] = link
+		saved = append(saved, link.Alias)
+	}
+
+	return saved, nil
+}
+
+func (s *Storage) GetURL(_ context.Context, alias string) (storage.Link, error) {
+	const op = "storage.memory.GetURL"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	link, ok := s.links[alias]
+	if !ok {
+		return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+	if link.DeletedAt != nil {
+		return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlDeleted)
+	}
+	if link.Expired(time.Now()) {
+		return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlExpired)
+	}
+
+	return link, nil
+}
+
+func (s *Storage) FindAlias(ctx context.Context, u string) (string, error) {
+	const op = "storage.memory.FindAlias"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found *storage.Link
+	for _, link := range s.links {
+		if link.URL != u || link.DeletedAt != nil || link.ExpiresAt != nil || !storage.Owns(ctx, link.OwnerID) {
+			continue
+		}
+		if found == nil || link.ID < found.ID {
+			found = &link
+		}
+	}
+	if found == nil {
+		return "", fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	return found.Alias, nil
+}
+
+func (s *Storage) TakenAliases(_ context.Context, aliases []string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var taken []string
+	for _, alias := range aliases {
+		if _, ok := s.links[alias]; ok {
+			taken = append(taken, alias)
+		}
+	}
+
+	return taken, nil
+}
+
+func (s *Storage) DeleteURL(ctx context.Context, alias string) (string, error) {
+	const op = "storage.memory.DeleteURL"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.links[alias]
+	if !ok || link.DeletedAt != nil || !storage.Owns(ctx, link.OwnerID) {
+		return "", fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+	now := time.Now()
+	link.DeletedAt = &now
+	s.links[alias] = link
+
+	return link.URL, nil
+}
+
+func (s *Storage) DeleteByOrigin(ctx context.Context, u string) ([]string, error) {
+	const op = "storage.memory.DeleteByOrigin"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var aliases []string
+	for alias, link := range s.links {
+		if link.URL == u && link.DeletedAt == nil && storage.Owns(ctx, link.OwnerID) {
+			link.DeletedAt = &now
+			s.links[alias] = link
+			aliases = append(aliases, alias)
+		}
+	}
+
+	if len(aliases) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	return aliases, nil
+}
+
+func (s *Storage) UpdateURL(ctx context.Context, upd storage.Update) (storage.Change, error) {
+	const op = "storage.memory.UpdateURL"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.links[upd.Alias]
+	if !ok || link.DeletedAt != nil || !storage.Owns(ctx, link.OwnerID) {
+		return storage.Change{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	change := storage.Change{
+		Version:      int64(len(s.history[upd.Alias])) + 1,
+		Alias:        upd.Alias,
+		OldURL:       link.URL,
+		OldExpiresAt: link.ExpiresAt,
+		ChangedBy:    upd.ChangedBy,
+		ChangedAt:    time.Now(),
+	}
+	change.NewURL, change.NewExpiresAt = upd.Apply(link.URL, link.ExpiresAt)
+
+	link.URL, link.ExpiresAt = change.NewURL, change.NewExpiresAt
+	s.links[upd.Alias] = link
+	s.history[upd.Alias] = append(s.history[upd.Alias], change)
+
+	return change, nil
+}
+
+func (s *Storage) GetHistory(ctx context.Context, alias string) ([]storage.Change, error) {
+	const op = "storage.memory.GetHistory"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if link, ok := s.links[alias]; !ok || !storage.Owns(ctx, link.OwnerID) {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	return append([]storage.Change(nil), s.history[alias]...), nil
+}
+
+func (s *Storage) FlagURL(_ context.Context, alias, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.links[alias]
+	if !ok || link.FlaggedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	link.FlaggedAt = &now
+	link.FlagReason = reason
+	s.links[alias] = link
+
+	return nil
+}
+
+func (s *Storage) RestoreURL(ctx context.Context, alias string) (storage.Link, error) {
+	const op = "storage.memory.RestoreURL"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.links[alias]
+	if !ok || link.DeletedAt == nil || !storage.Owns(ctx, link.OwnerID) {
+		return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+	link.DeletedAt = nil
+	s.links[alias] = link
+
+	return link, nil
+}
+
+func (s *Storage) PurgeDeleted(_ context.Context, before time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := make(map[string]struct{})
+	for alias, link := range s.links {
+		if link.DeletedAt != nil && link.DeletedAt.Before(before) {
+			delete(s.links, alias)
+			delete(s.history, alias)
+			purged[alias] = struct{}{}
+		}
+	}
+	if len(purged) == 0 {
+		return nil, nil
+	}
+
+	clicks := s.clicks[:0]
+	for _, c := range s.clicks {
+		if _, ok := purged[c.Alias]; !ok {
+			clicks = append(clicks, c)
+		}
+	}
+	s.clicks = clicks
+
+	aliases := make([]string, 0, len(purged))
+	for alias := range purged {
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, nil
+}
+
+func (s *Storage) FoldAliases(_ context.Context) error {
+	const op = "storage.memory.FoldAliases"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	folded := make(map[string]int, len(s.links))
+	for alias := range s.links {
+		folded[strings.ToLower(alias)]++
+	}
+	var conflicts []string
+	for alias, n := range folded {
+		if n > 1 {
+			conflicts = append(conflicts, alias)
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("%s: %w: %s", op, storage.ErrAliasCaseConflict, strings.Join(conflicts, ", "))
+	}
+
+	for alias, link := range s.links {
+		key := strings.ToLower(alias)
+		if key == alias {
+			continue
+		}
+		delete(s.links, alias)
+		link.Alias = key
+		s.links[key] = link
+
+		if history, ok := s.history[alias]; ok {
+			delete(s.history, alias)
+			for i := range history {
+				history[i].Alias = key
+			}
+			s.history[key] = history
+		}
+	}
+	for i, c := range s.clicks {
+		s.clicks[i].Alias = strings.ToLower(c.Alias)
+	}
+
+	return nil
+}
+
+func (s *Storage) NextSequence(_ context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	return s.seq, nil
+}
+
+func (s *Storage) SaveClicks(_ context.Context, clicks []storage.Click) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clicks = append(s.clicks, clicks...)
+
+	return nil
+}
+
+func (s *Storage) GetStats(ctx context.Context, q storage.StatsQuery) (storage.Stats, error) {
+	const op = "storage.memory.GetStats"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if link, ok := s.links[q.Alias]; !ok || !storage.Owns(ctx, link.OwnerID) {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	step := q.Interval.Duration()
+	visitors := make(map[string]struct{})
+	bucketVisitors := make(map[time.Time]map[string]struct{})
+	buckets := make(map[time.Time]*storage.StatsBucket)
+	referrers := make(map[string]int64)
+	agents := storage.FamilyCounts{}
+
+	var stats storage.Stats
+	for _, c := range s.clicks {
+		if c.Alias != q.Alias || c.ClickedAt.Before(q.From) || !c.ClickedAt.Before(q.To) {
+			continue
+		}
+
+		stats.TotalClicks++
+		visitors[c.IPHash] = struct{}{}
+
+		start := c.ClickedAt.UTC().Truncate(step)
+		b, ok := buckets[start]
+		if !ok {
+			b = &storage.StatsBucket{Start: start}
+			buckets[start] = b
+			bucketVisitors[start] = make(map[string]struct{})
+		}
+		b.Clicks++
+		bucketVisitors[start][c.IPHash] = struct{}{}
+
+		if c.Referrer != "" {
+			referrers[c.Referrer]++
+		}
+		agents.Add(c.UserAgent, 1)
+	}
+	stats.UniqueVisitors = int64(len(visitors))
+
+	for start, b := range buckets {
+		b.UniqueVisitors = int64(len(bucketVisitors[start]))
+		stats.Buckets = append(stats.Buckets, *b)
+	}
+
+	stats.TopReferrers = topCounts(referrers, storage.TopReferrersLimit)
+	stats.UserAgents = agents.Sorted()
+
+	return stats, nil
+}
+
+func topCounts(counts map[string]int64, limit int) []storage.StatsCount {
+	top := make([]storage.StatsCount, 0, len(counts))
+	for value, clicks := range counts {
+		top = append(top, storage.StatsCount{Value: value, Clicks: clicks})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Clicks != top[j].Clicks {
+			return top[i].Clicks > top[j].Clicks
+		}
+		return top[i].Value < top[j].Value
+	})
+
+	if limit > 0 && len(top) > limit {
+		top = top[:limit]
+	}
+
+	return top
+}
+
+func (s *Storage) ListURLs(ctx context.Context, q storage.ListQuery) ([]storage.Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	less := func(a, b storage.Link) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	}
+	if q.Order != storage.OrderAsc {
+		asc := less
+		less = func(a, b storage.Link) bool { return asc(b, a) }
+	}
+
+	var links []storage.Link
+	for _, link := range s.links {
+		if !q.Matches(link) || !storage.Owns(ctx, link.OwnerID) {
+			continue
+		}
+		if q.After != nil && !less(storage.Link{CreatedAt: q.After.CreatedAt, ID: q.After.ID}, link) {
+			continue
+		}
+		links = append(links, link)
+	}
+
+	sort.Slice(links, func(i, j int) bool { return less(links[i], links[j]) })
+	if len(links) > q.Limit {
+		links = links[:q.Limit]
+	}
+
+	return links, nil
+}
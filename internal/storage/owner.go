@@ -0,0 +1,52 @@
+package storage
+
+import "context"
+
+// Caller is the user on whose behalf links are accessed. Links created by
+// a caller are owned by it, and unless it is an admin, deleting, updating,
+// listing and reading the stats or history of links is limited to the
+// links it owns.
+type Caller struct {
+	UserID int64
+	Admin  bool
+}
+
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx carrying c. Contexts without a caller,
+// like those of redirects, background jobs and the CLI, are unrestricted.
+func WithCaller(ctx context.Context, c Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, c)
+}
+
+func CallerFrom(ctx context.Context) (Caller, bool) {
+	c, ok := ctx.Value(callerKey{}).(Caller)
+	return c, ok
+}
+
+// OwnerScope returns the user whose links the caller of ctx is limited to,
+// or false if it may access every link. Backends add it to the conditions
+// of scoped queries, so links of other users look as if they did not exist.
+func OwnerScope(ctx context.Context) (int64, bool) {
+	c, ok := CallerFrom(ctx)
+	if !ok || c.Admin {
+		return 0, false
+	}
+	return c.UserID, true
+}
+
+// Owns reports whether the caller of ctx may access a link owned by the
+// given user. Backends that filter in SQL do not need it.
+func Owns(ctx context.Context, ownerID int64) bool {
+	id, scoped := OwnerScope(ctx)
+	return !scoped || (ownerID != 0 && id == ownerID)
+}
+
+// owner returns the user recorded as the owner of a link created with
+// ctx. Links created without a caller keep the owner they were given.
+func owner(ctx context.Context, link Link) int64 {
+	if c, ok := CallerFrom(ctx); ok {
+		return c.UserID
+	}
+	return link.OwnerID
+}
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE url ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE url DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE url ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE url ADD COLUMN IF NOT EXISTS host TEXT NOT NULL DEFAULT '';
+
+-- The host is backfilled like storage.HostOf: userinfo ends at the last "@"
+-- of the authority, and IPv6 hosts are the inside of "[...]".
+UPDATE url
+SET host = lower(COALESCE(parsed.m[1], parsed.m[2], ''))
+FROM (
+    SELECT id, regexp_match(origin, '^[A-Za-z][A-Za-z0-9+.-]*://(?:[^/?#]*@)?(?:\[([^]]*)\]|([^/?#:@]*))') AS m
+    FROM url
+) AS parsed
+WHERE parsed.id = url.id AND url.host = '';
+
+CREATE INDEX IF NOT EXISTS idx_url_created_at_id ON url(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_url_host ON url(host);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_url_host;
+DROP INDEX IF EXISTS idx_url_created_at_id;
+ALTER TABLE url DROP COLUMN IF EXISTS host;
+ALTER TABLE url DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE url_history ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE url_history ADD COLUMN IF NOT EXISTS old_expires_at TIMESTAMPTZ;
+ALTER TABLE url_history ADD COLUMN IF NOT EXISTS new_expires_at TIMESTAMPTZ;
+ALTER TABLE url_history ADD COLUMN IF NOT EXISTS changed_by TEXT NOT NULL DEFAULT '';
+
+UPDATE url_history
+SET version = (
+    SELECT COUNT(*)
+    FROM url_history h
+    WHERE h.alias = url_history.alias AND h.id <= url_history.id
+);
+
+DROP INDEX IF EXISTS idx_url_history_alias;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_url_history_alias_version ON url_history(alias, version);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_url_history_alias_version;
+CREATE INDEX IF NOT EXISTS idx_url_history_alias ON url_history(alias, id);
+ALTER TABLE url_history DROP COLUMN IF EXISTS changed_by;
+ALTER TABLE url_history DROP COLUMN IF EXISTS new_expires_at;
+ALTER TABLE url_history DROP COLUMN IF EXISTS old_expires_at;
+ALTER TABLE url_history DROP COLUMN IF EXISTS version;
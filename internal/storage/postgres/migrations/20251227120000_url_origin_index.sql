@@ -0,0 +1,6 @@
+-- +goose Up
+-- A hash index has no key size limit, so it works for arbitrarily long origins.
+CREATE INDEX IF NOT EXISTS idx_url_origin ON url USING hash (origin);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_url_origin;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE SEQUENCE IF NOT EXISTS alias_seq;
+
+-- +goose Down
+DROP SEQUENCE IF EXISTS alias_seq;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE url ADD COLUMN IF NOT EXISTS flagged_at TIMESTAMPTZ;
+ALTER TABLE url ADD COLUMN IF NOT EXISTS flag_reason TEXT;
+
+-- +goose Down
+ALTER TABLE url DROP COLUMN IF EXISTS flag_reason;
+ALTER TABLE url DROP COLUMN IF EXISTS flagged_at;
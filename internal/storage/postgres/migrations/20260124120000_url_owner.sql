@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE url ADD COLUMN IF NOT EXISTS owner_id BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_url_owner_id ON url(owner_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_url_owner_id;
+ALTER TABLE url DROP COLUMN IF EXISTS owner_id;
@@ -6,6 +6,10 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/pressly/goose/v3"
@@ -17,6 +21,10 @@ type Storage struct {
 	db *sql.DB
 }
 
+// insertRows caps the rows of a single multi-row INSERT, keeping it well
+// below the protocol limit of 65535 bind parameters.
+const insertRows = 1000
+
 //go:embed migrations/*.sql
 var embedMigrations embed.FS
 
@@ -46,22 +54,30 @@ func runMigrations(db *sql.DB) error {
 		return fmt.Errorf("failed to set goose dialect: %w", err)
 	}
 
-	fmt.Println("Running migrations...")
+	log.Println("Running migrations...")
 	if err := goose.Up(db, "migrations"); err != nil {
 		return fmt.Errorf("failed to apply migrations: %w", err)
 	}
 
-	fmt.Println("Migrations applied successfully")
+	log.Println("Migrations applied successfully")
 	return nil
 }
 
-func (s *Storage) SaveURL(ctx context.Context, u, alias string) error {
+// scope returns the owner the caller of ctx is limited to as a query
+// argument. It is NULL for unrestricted callers, so queries check it with
+// `($n::bigint IS NULL OR owner_id = $n)`.
+func scope(ctx context.Context) sql.NullInt64 {
+	id, ok := storage.OwnerScope(ctx)
+	return sql.NullInt64{Int64: id, Valid: ok}
+}
+
+func (s *Storage) SaveURL(ctx context.Context, link storage.Link) error {
 	const op = "storage.postgres.SaveUrl"
 
 	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO url (alias, origin)
-		VALUES ($1, $2);
-	`, alias, u)
+		INSERT INTO url (alias, origin, host, created_at, expires_at, owner_id)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6::bigint, 0));
+	`, link.Alias, link.URL, storage.HostOf(link.URL), link.CreatedAt, link.ExpiresAt, link.OwnerID)
 	if err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
@@ -73,15 +89,105 @@ func (s *Storage) SaveURL(ctx context.Context, u, alias string) error {
 	return nil
 }
 
-func (s *Storage) GetURL(ctx context.Context, alias string) (string, error) {
+// SaveURLs inserts links in a single transaction and returns the aliases
+// that were stored. Links whose alias is already taken are skipped.
+func (s *Storage) SaveURLs(ctx context.Context, links []storage.Link) ([]string, error) {
+	const op = "storage.postgres.SaveURLs"
+
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var saved []string
+	for start := 0; start < len(links); start += insertRows {
+		chunk := links[start:min(start+insertRows, len(links))]
+
+		values := make([]string, len(chunk))
+		args := make([]any, 0, len(chunk)*6)
+		for i, link := range chunk {
+			n := i * 6
+			values[i] = fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, NULLIF($%d::bigint, 0))", n+1, n+2, n+3, n+4, n+5, n+6)
+			args = append(args, link.Alias, link.URL, storage.HostOf(link.URL), link.CreatedAt, link.ExpiresAt, link.OwnerID)
+		}
+
+		rows, err := tx.QueryContext(ctx, `
+			INSERT INTO url (alias, origin, host, created_at, expires_at, owner_id)
+			VALUES `+strings.Join(values, ", ")+`
+			ON CONFLICT (alias) DO NOTHING
+			RETURNING alias;
+		`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for rows.Next() {
+			var alias string
+			if err := rows.Scan(&alias); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			saved = append(saved, alias)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return saved, nil
+}
+
+func (s *Storage) GetURL(ctx context.Context, alias string) (storage.Link, error) {
 	const op = "storage.postgres.GetUrl"
 
-	var u string
+	link := storage.Link{Alias: alias}
+	var expiresAt, deletedAt sql.NullTime
 	err := s.db.QueryRowContext(ctx, `
-		SELECT origin
+		SELECT origin, expires_at, deleted_at
 		FROM url
 		WHERE alias = $1;
-	`, alias).Scan(&u)
+	`, alias).Scan(&link.URL, &expiresAt, &deletedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+		}
+		return storage.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if deletedAt.Valid {
+		return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlDeleted)
+	}
+	if expiresAt.Valid {
+		link.ExpiresAt = &expiresAt.Time
+	}
+
+	if link.Expired(time.Now()) {
+		return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlExpired)
+	}
+
+	return link, nil
+}
+
+func (s *Storage) FindAlias(ctx context.Context, u string) (string, error) {
+	const op = "storage.postgres.FindAlias"
+
+	var alias string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT alias
+		FROM url
+		WHERE origin = $1 AND deleted_at IS NULL AND expires_at IS NULL
+			AND ($2::bigint IS NULL OR owner_id = $2)
+		ORDER BY id
+		LIMIT 1;
+	`, u, scope(ctx)).Scan(&alias)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
@@ -89,7 +195,39 @@ func (s *Storage) GetURL(ctx context.Context, alias string) (string, error) {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	return u, nil
+	return alias, nil
+}
+
+func (s *Storage) TakenAliases(ctx context.Context, aliases []string) ([]string, error) {
+	const op = "storage.postgres.TakenAliases"
+
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT alias
+		FROM url
+		WHERE alias = ANY($1);
+	`, pq.Array(aliases))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var taken []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		taken = append(taken, alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return taken, nil
 }
 
 func (s *Storage) DeleteURL(ctx context.Context, alias string) (string, error) {
@@ -97,10 +235,11 @@ func (s *Storage) DeleteURL(ctx context.Context, alias string) (string, error) {
 
 	var u string
 	err := s.db.QueryRowContext(ctx, `
-		DELETE FROM url
-		WHERE origin = (SELECT origin FROM url WHERE alias = $1)
+		UPDATE url
+		SET deleted_at = $2
+		WHERE alias = $1 AND deleted_at IS NULL AND ($3::bigint IS NULL OR owner_id = $3)
 		RETURNING origin
-	`, alias).Scan(&u)
+	`, alias, time.Now(), scope(ctx)).Scan(&u)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -111,3 +250,546 @@ func (s *Storage) DeleteURL(ctx context.Context, alias string) (string, error) {
 
 	return u, nil
 }
+
+func (s *Storage) DeleteByOrigin(ctx context.Context, u string) ([]string, error) {
+	const op = "storage.postgres.DeleteByOrigin"
+
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE url
+		SET deleted_at = $2
+		WHERE origin = $1 AND deleted_at IS NULL AND ($3::bigint IS NULL OR owner_id = $3)
+		RETURNING alias
+	`, u, time.Now(), scope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var aliases []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		aliases = append(aliases, alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(aliases) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	return aliases, nil
+}
+
+func (s *Storage) UpdateURL(ctx context.Context, upd storage.Update) (storage.Change, error) {
+	const op = "storage.postgres.UpdateURL"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return storage.Change{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	change := storage.Change{
+		Alias:     upd.Alias,
+		ChangedBy: upd.ChangedBy,
+		ChangedAt: time.Now(),
+	}
+	var expiresAt sql.NullTime
+	err = tx.QueryRowContext(ctx, `
+		SELECT origin, expires_at
+		FROM url
+		WHERE alias = $1 AND deleted_at IS NULL AND ($2::bigint IS NULL OR owner_id = $2)
+		FOR UPDATE;
+	`, upd.Alias, scope(ctx)).Scan(&change.OldURL, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.Change{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+		}
+		return storage.Change{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if expiresAt.Valid {
+		change.OldExpiresAt = &expiresAt.Time
+	}
+	change.NewURL, change.NewExpiresAt = upd.Apply(change.OldURL, change.OldExpiresAt)
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE url
+		SET origin = $2, host = $3, expires_at = $4
+		WHERE alias = $1;
+	`, upd.Alias, change.NewURL, storage.HostOf(change.NewURL), change.NewExpiresAt)
+	if err != nil {
+		return storage.Change{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO url_history (alias, version, old_origin, new_origin, old_expires_at, new_expires_at, changed_by, changed_at)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2::text, $3::text, $4::timestamptz, $5::timestamptz, $6::text, $7::timestamptz
+		FROM url_history
+		WHERE alias = $1
+		RETURNING version;
+	`, upd.Alias, change.OldURL, change.NewURL, change.OldExpiresAt, change.NewExpiresAt,
+		change.ChangedBy, change.ChangedAt).Scan(&change.Version)
+	if err != nil {
+		return storage.Change{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.Change{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return change, nil
+}
+
+func (s *Storage) GetHistory(ctx context.Context, alias string) ([]storage.Change, error) {
+	const op = "storage.postgres.GetHistory"
+
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM url WHERE alias = $1 AND ($2::bigint IS NULL OR owner_id = $2));
+	`, alias, scope(ctx)).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT version, old_origin, new_origin, old_expires_at, new_expires_at, changed_by, changed_at
+		FROM url_history
+		WHERE alias = $1
+		ORDER BY version;
+	`, alias)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var history []storage.Change
+	for rows.Next() {
+		c := storage.Change{Alias: alias}
+		var oldExpiresAt, newExpiresAt sql.NullTime
+		if err := rows.Scan(&c.Version, &c.OldURL, &c.NewURL, &oldExpiresAt, &newExpiresAt, &c.ChangedBy, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if oldExpiresAt.Valid {
+			c.OldExpiresAt = &oldExpiresAt.Time
+		}
+		if newExpiresAt.Valid {
+			c.NewExpiresAt = &newExpiresAt.Time
+		}
+		history = append(history, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return history, nil
+}
+
+func (s *Storage) NextSequence(ctx context.Context) (int64, error) {
+	const op = "storage.postgres.NextSequence"
+
+	var n int64
+	if err := s.db.QueryRowContext(ctx, `SELECT nextval('alias_seq');`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
+
+func (s *Storage) SaveClicks(ctx context.Context, clicks []storage.Click) error {
+	const op = "storage.postgres.SaveClicks"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("clicks", "alias", "clicked_at", "referrer", "user_agent", "ip_hash"))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	for _, c := range clicks {
+		if _, err := stmt.ExecContext(ctx, c.Alias, c.ClickedAt, c.Referrer, c.UserAgent, c.IPHash); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// An argument-less Exec flushes the buffered COPY data.
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetStats(ctx context.Context, q storage.StatsQuery) (storage.Stats, error) {
+	const op = "storage.postgres.GetStats"
+
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM url WHERE alias = $1 AND ($2::bigint IS NULL OR owner_id = $2));
+	`, q.Alias, scope(ctx)).Scan(&exists)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	var stats storage.Stats
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(DISTINCT ip_hash)
+		FROM clicks
+		WHERE alias = $1 AND clicked_at >= $2 AND clicked_at < $3;
+	`, q.Alias, q.From, q.To).Scan(&stats.TotalClicks, &stats.UniqueVisitors)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT date_trunc($4, clicked_at, 'UTC') AS bucket, COUNT(*), COUNT(DISTINCT ip_hash)
+		FROM clicks
+		WHERE alias = $1 AND clicked_at >= $2 AND clicked_at < $3
+		GROUP BY bucket
+		ORDER BY bucket;
+	`, q.Alias, q.From, q.To, string(q.Interval))
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b storage.StatsBucket
+		if err := rows.Scan(&b.Start, &b.Clicks, &b.UniqueVisitors); err != nil {
+			return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+		}
+		stats.Buckets = append(stats.Buckets, b)
+	}
+	if err := rows.Err(); err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats.TopReferrers, err = s.queryCounts(ctx, `
+		SELECT referrer, COUNT(*) AS clicks
+		FROM clicks
+		WHERE alias = $1 AND clicked_at >= $2 AND clicked_at < $3 AND referrer <> ''
+		GROUP BY referrer
+		ORDER BY clicks DESC, referrer
+		LIMIT $4;
+	`, q.Alias, q.From, q.To, storage.TopReferrersLimit)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats.UserAgents, err = s.queryFamilies(ctx, `
+		SELECT user_agent, COUNT(*) AS clicks
+		FROM clicks
+		WHERE alias = $1 AND clicked_at >= $2 AND clicked_at < $3
+		GROUP BY user_agent;
+	`, q.Alias, q.From, q.To)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func (s *Storage) queryCounts(ctx context.Context, query string, args ...any) ([]storage.StatsCount, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []storage.StatsCount
+	for rows.Next() {
+		var c storage.StatsCount
+		if err := rows.Scan(&c.Value, &c.Clicks); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+// queryFamilies reads user agent counts like queryCounts, folding them into
+// browser families as they are scanned.
+func (s *Storage) queryFamilies(ctx context.Context, query string, args ...any) ([]storage.StatsCount, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	families := storage.FamilyCounts{}
+	for rows.Next() {
+		var (
+			userAgent string
+			clicks    int64
+		)
+		if err := rows.Scan(&userAgent, &clicks); err != nil {
+			return nil, err
+		}
+		families.Add(userAgent, clicks)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return families.Sorted(), nil
+}
+
+func (s *Storage) ListURLs(ctx context.Context, q storage.ListQuery) ([]storage.Link, error) {
+	const op = "storage.postgres.ListURLs"
+
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if q.Search != "" {
+		where = append(where, "origin ILIKE '%' || "+arg(storage.EscapeLike(q.Search))+" || '%'")
+	}
+	if q.Host != "" {
+		where = append(where, "host = "+arg(q.Host))
+	}
+	if q.Trashed {
+		where = append(where, "deleted_at IS NOT NULL")
+	} else {
+		where = append(where, "deleted_at IS NULL")
+	}
+	if owner, ok := storage.OwnerScope(ctx); ok {
+		where = append(where, "owner_id = "+arg(owner))
+	}
+
+	cmp, dir := "<", "DESC"
+	if q.Order == storage.OrderAsc {
+		cmp, dir = ">", "ASC"
+	}
+	if q.After != nil {
+		where = append(where, fmt.Sprintf("(created_at, id) %s (%s, %s)", cmp, arg(q.After.CreatedAt), arg(q.After.ID)))
+	}
+
+	query := "SELECT id, alias, origin, created_at, expires_at, deleted_at, flagged_at, flag_reason, COALESCE(owner_id, 0) FROM url WHERE " + strings.Join(where, " AND ")
+	query += fmt.Sprintf(" ORDER BY created_at %s, id %s LIMIT %s", dir, dir, arg(q.Limit))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var links []storage.Link
+	for rows.Next() {
+		var (
+			link                            storage.Link
+			expiresAt, deletedAt, flaggedAt sql.NullTime
+			flagReason                      sql.NullString
+		)
+		if err := rows.Scan(&link.ID, &link.Alias, &link.URL, &link.CreatedAt, &expiresAt, &deletedAt, &flaggedAt, &flagReason, &link.OwnerID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if expiresAt.Valid {
+			link.ExpiresAt = &expiresAt.Time
+		}
+		if deletedAt.Valid {
+			link.DeletedAt = &deletedAt.Time
+		}
+		if flaggedAt.Valid {
+			link.FlaggedAt = &flaggedAt.Time
+			link.FlagReason = flagReason.String
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return links, nil
+}
+
+func (s *Storage) FlagURL(ctx context.Context, alias, reason string) error {
+	const op = "storage.postgres.FlagURL"
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE url
+		SET flagged_at = $2, flag_reason = $3
+		WHERE alias = $1 AND flagged_at IS NULL;
+	`, alias, time.Now(), reason)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) RestoreURL(ctx context.Context, alias string) (storage.Link, error) {
+	const op = "storage.postgres.RestoreURL"
+
+	link := storage.Link{Alias: alias}
+	var expiresAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE url
+		SET deleted_at = NULL
+		WHERE alias = $1 AND deleted_at IS NOT NULL AND ($2::bigint IS NULL OR owner_id = $2)
+		RETURNING id, origin, created_at, expires_at, COALESCE(owner_id, 0)
+	`, alias, scope(ctx)).Scan(&link.ID, &link.URL, &link.CreatedAt, &expiresAt, &link.OwnerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+		}
+		return storage.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if expiresAt.Valid {
+		link.ExpiresAt = &expiresAt.Time
+	}
+
+	return link, nil
+}
+
+func (s *Storage) PurgeDeleted(ctx context.Context, before time.Time) ([]string, error) {
+	const op = "storage.postgres.PurgeDeleted"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"url_history", "clicks"} {
+		_, err := tx.ExecContext(ctx, `
+			DELETE FROM `+table+`
+			WHERE alias IN (SELECT alias FROM url WHERE deleted_at < $1);
+		`, before)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		DELETE FROM url
+		WHERE deleted_at < $1
+		RETURNING alias
+	`, before)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var aliases []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		aliases = append(aliases, alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows.Close()
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return aliases, nil
+}
+
+func (s *Storage) FoldAliases(ctx context.Context) error {
+	const op = "storage.postgres.FoldAliases"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Keep other instances from storing aliases between the check and the
+	// creation of the index.
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE url IN SHARE ROW EXCLUSIVE MODE;`); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conflicts, err := caseConflicts(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%s: %w: %s", op, storage.ErrAliasCaseConflict, strings.Join(conflicts, ", "))
+	}
+
+	for _, table := range []string{"url_history", "clicks", "url"} {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE `+table+`
+			SET alias = lower(alias)
+			WHERE alias <> lower(alias);
+		`)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_url_alias_lower ON url(lower(alias));
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// caseConflicts returns up to ten aliases, lower-cased, that are stored in
+// more than one case.
+func caseConflicts(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT lower(alias)
+		FROM url
+		GROUP BY lower(alias)
+		HAVING COUNT(*) > 1
+		ORDER BY 1
+		LIMIT 10;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var conflicts []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, alias)
+	}
+
+	return conflicts, rows.Err()
+}
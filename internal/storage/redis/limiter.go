@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucket takes a token from the bucket stored at KEYS[1], refilled by
+// one token every ARGV[1] microseconds up to ARGV[2] tokens. It returns
+// whether a token was taken and otherwise the microseconds until the next
+// one. The Redis clock is used so that all instances agree on the time.
+var tokenBucket = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'last')
+local tokens = tonumber(state[1])
+local last = tonumber(state[2])
+if tokens == nil or last == nil then
+	tokens, last = burst, now
+end
+tokens = math.min(burst, tokens + math.max(0, now - last) / interval)
+
+if tokens < 1 then
+	return {0, math.ceil((1 - tokens) * interval)}
+end
+
+-- Numbers are formatted explicitly, as Redis would round the timestamp
+-- to 14 significant digits.
+tokens = tokens - 1
+redis.call('HSET', KEYS[1], 'tokens', string.format('%.6f', tokens), 'last', string.format('%d', now))
+redis.call('PEXPIRE', KEYS[1], string.format('%d', math.ceil((burst - tokens) * interval / 1000) + 1000))
+return {1, 0}
+`)
+
+// Limiter is a token bucket rate limiter keeping its buckets in Redis, so
+// that every instance of a deployment shares them.
+type Limiter struct {
+	client *redis.Client
+	cfg    *config.RateLimitConfig
+}
+
+func NewLimiter(connStr string, cfg *config.RateLimitConfig) (*Limiter, error) {
+	const op = "storage.redis.NewLimiter"
+
+	if connStr == "" {
+		return nil, fmt.Errorf("%s: rate limiting backed by redis but connection string empty", op)
+	}
+
+	client, err := connect(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Limiter{client: client, cfg: cfg}, nil
+}
+
+// Allow takes a token from the bucket of key. If the bucket is empty, it
+// reports how long until the next token is added instead.
+func (l *Limiter) Allow(ctx context.Context, key string, limit config.RateLimit) (bool, time.Duration, error) {
+	const op = "storage.redis.Allow"
+
+	interval := (limit.Period / time.Duration(limit.Rate)).Microseconds()
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = limit.Rate
+	}
+
+	res, err := tokenBucket.Run(ctx, l.client, []string{l.cfg.Prefix + key}, max(interval, 1), burst).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("%s: %s", op, err)
+	}
+	if len(res) != 2 {
+		return false, 0, fmt.Errorf("%s: unexpected script result %v", op, res)
+	}
+
+	return res[0] == 1, time.Duration(res[1]) * time.Microsecond, nil
+}
+
+func (l *Limiter) Close() error {
+	return l.client.Close()
+}
@@ -6,8 +6,10 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -27,6 +29,15 @@ func New(connStr string, cfg *config.CacheConfig) (*Storage, error) {
 		return nil, fmt.Errorf("%s: cache enabled but connection string empty", op)
 	}
 
+	client, err := connect(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Storage{client: client, cfg: cfg}, nil
+}
+
+func connect(connStr string) (*redis.Client, error) {
 	u, err := url.Parse(connStr)
 	if err != nil {
 		return nil, err
@@ -43,22 +54,32 @@ func New(connStr string, cfg *config.CacheConfig) (*Storage, error) {
 	client := redis.NewClient(&redis.Options{Addr: addr, DB: db})
 
 	if err := client.Ping(context.Background()).Err(); err != nil {
-		return nil, fmt.Errorf("%s: unable to connect: %w", op, err)
+		return nil, fmt.Errorf("unable to connect: %w", err)
 	}
 
-	return &Storage{client: client, cfg: cfg}, nil
+	return client, nil
 }
 
-func (s *Storage) SetURL(ctx context.Context, u, alias string) error {
+func (s *Storage) SetURL(ctx context.Context, u, alias string, expiresAt *time.Time) error {
 	const op = "storage.redis.SetURL"
 
-	if err := s.client.Set(ctx, s.cfg.PrefixURL+alias, u, s.cfg.TTL).Err(); err != nil {
-		return fmt.Errorf("%s: %s", op, err)
+	ttl, ok := storage.CacheTTL(s.cfg.TTL, expiresAt)
+	if !ok {
+		return nil
 	}
-	if err := s.client.Set(ctx, s.cfg.PrefixRev+u, alias, s.cfg.ReverseIndexTTL).Err(); err != nil {
+
+	if err := s.client.Set(ctx, s.cfg.PrefixURL+alias, u, ttl).Err(); err != nil {
 		return fmt.Errorf("%s: %s", op, err)
 	}
 
+	// Only permanent links are reused, so links with an expiration are kept
+	// out of the reverse index.
+	if expiresAt == nil {
+		if err := s.client.Set(ctx, s.cfg.PrefixRev+u, alias, s.cfg.ReverseIndexTTL).Err(); err != nil {
+			return fmt.Errorf("%s: %s", op, err)
+		}
+	}
+
 	return nil
 }
 
@@ -73,6 +94,17 @@ func (s *Storage) GetURL(ctx context.Context, alias string) (string, error) {
 	return u, nil
 }
 
+func (s *Storage) GetAlias(ctx context.Context, u string) (string, error) {
+	const op = "storage.redis.GetAlias"
+
+	alias, err := s.client.Get(ctx, s.cfg.PrefixRev+u).Result()
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", op, err)
+	}
+
+	return alias, nil
+}
+
 func (s *Storage) DelURL(ctx context.Context, u, alias string) error {
 	const op = "storage.redis.DelURL"
 
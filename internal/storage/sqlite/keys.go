@@ -0,0 +1,212 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+func (s *Storage) CreateUser(ctx context.Context, name string) (storage.User, error) {
+	const op = "storage.sqlite.CreateUser"
+
+	user := storage.User{Name: name, CreatedAt: time.Now()}
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO users (name, created_at)
+		VALUES (?, ?)
+		RETURNING id;
+	`, user.Name, user.CreatedAt.UTC()).Scan(&user.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+		}
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+func (s *Storage) GetUser(ctx context.Context, name string) (storage.User, error) {
+	const op = "storage.sqlite.GetUser"
+
+	user := storage.User{Name: name}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, created_at
+		FROM users
+		WHERE name = ?;
+	`, name).Scan(&user.ID, &user.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+		return storage.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+func (s *Storage) ListUsers(ctx context.Context) ([]storage.User, error) {
+	const op = "storage.sqlite.ListUsers"
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, name, created_at
+		FROM users
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	users := []storage.User{}
+	for rows.Next() {
+		var user storage.User
+		if err := rows.Scan(&user.ID, &user.Name, &user.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+func (s *Storage) CreateAPIKey(ctx context.Context, key storage.APIKey) (storage.APIKey, error) {
+	const op = "storage.sqlite.CreateAPIKey"
+
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, created_at)
+		SELECT id, ?, ?, ?, ?, ?
+		FROM users
+		WHERE id = ?
+		RETURNING id;
+	`, key.Name, key.Prefix, key.Hash, strings.Join(key.Scopes, " "), key.CreatedAt.UTC(), key.UserID).Scan(&key.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.APIKey{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+		return storage.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (s *Storage) ListAPIKeys(ctx context.Context, userID int64) ([]storage.APIKey, error) {
+	const op = "storage.sqlite.ListAPIKeys"
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT k.id, k.user_id, u.name, k.name, k.prefix, k.key_hash, k.scopes, k.created_at, k.last_used_at, k.revoked_at
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.user_id = ?
+		ORDER BY k.id;
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	keys := []storage.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+func (s *Storage) FindAPIKey(ctx context.Context, hash string) (storage.APIKey, error) {
+	const op = "storage.sqlite.FindAPIKey"
+
+	key, err := scanAPIKey(s.db.QueryRowContext(ctx, `
+		SELECT k.id, k.user_id, u.name, k.name, k.prefix, k.key_hash, k.scopes, k.created_at, k.last_used_at, k.revoked_at
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.key_hash = ?;
+	`, hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.APIKey{}, fmt.Errorf("%s: %w", op, storage.ErrKeyNotFound)
+		}
+		return storage.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (s *Storage) TouchAPIKey(ctx context.Context, id int64, at time.Time) error {
+	const op = "storage.sqlite.TouchAPIKey"
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE api_keys
+		SET last_used_at = ?
+		WHERE id = ?;
+	`, at.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) RevokeAPIKey(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.RevokeAPIKey"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE api_keys
+		SET revoked_at = ?
+		WHERE id = ? AND revoked_at IS NULL;
+	`, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrKeyNotFound)
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row scanner) (storage.APIKey, error) {
+	var key storage.APIKey
+	var scopes string
+	var lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.UserID, &key.UserName, &key.Name, &key.Prefix, &key.Hash,
+		&scopes, &key.CreatedAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return storage.APIKey{}, err
+	}
+	key.Scopes = strings.Fields(scopes)
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return key, nil
+}
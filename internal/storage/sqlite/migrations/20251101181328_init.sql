@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS url (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    alias TEXT NOT NULL UNIQUE,
+    origin TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_alias ON url(alias);
+
+-- +goose Down
+DROP TABLE IF EXISTS url;
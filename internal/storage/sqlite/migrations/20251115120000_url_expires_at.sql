@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE url ADD COLUMN expires_at DATETIME;
+
+-- +goose Down
+ALTER TABLE url DROP COLUMN expires_at;
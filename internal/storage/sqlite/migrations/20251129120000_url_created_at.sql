@@ -0,0 +1,34 @@
+-- +goose Up
+ALTER TABLE url ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00';
+ALTER TABLE url ADD COLUMN host TEXT NOT NULL DEFAULT '';
+
+UPDATE url SET created_at = strftime('%Y-%m-%d %H:%M:%S+00:00', 'now');
+
+-- The host is backfilled like storage.HostOf: the authority runs from "://"
+-- to the first of "/", "?" or "#", userinfo ends at its last "@", and the
+-- host is the inside of "[...]" for IPv6 or what precedes ":" otherwise.
+WITH RECURSIVE authority(id, rest) AS (
+    SELECT id, substr(rest, 1, instr(replace(replace(rest, '?', '/'), '#', '/') || '/', '/') - 1)
+    FROM (SELECT id, substr(origin, instr(origin, '://') + 3) AS rest FROM url WHERE instr(origin, '://') > 0)
+    UNION ALL
+    SELECT id, substr(rest, instr(rest, '@') + 1) FROM authority WHERE instr(rest, '@') > 0
+)
+UPDATE url
+SET host = (
+    SELECT lower(CASE
+        WHEN rest LIKE '[%' AND instr(rest, ']') > 0 THEN substr(rest, 2, instr(rest, ']') - 2)
+        ELSE substr(rest, 1, instr(rest || ':', ':') - 1)
+    END)
+    FROM authority
+    WHERE authority.id = url.id AND instr(rest, '@') = 0
+)
+WHERE instr(origin, '://') > 0;
+
+CREATE INDEX IF NOT EXISTS idx_url_created_at_id ON url(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_url_host ON url(host);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_url_host;
+DROP INDEX IF EXISTS idx_url_created_at_id;
+ALTER TABLE url DROP COLUMN host;
+ALTER TABLE url DROP COLUMN created_at;
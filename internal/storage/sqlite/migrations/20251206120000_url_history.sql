@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS url_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    alias TEXT NOT NULL,
+    old_origin TEXT NOT NULL,
+    new_origin TEXT NOT NULL,
+    changed_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_url_history_alias ON url_history(alias, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS url_history;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE url ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_url_deleted_at ON url(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_url_deleted_at;
+ALTER TABLE url DROP COLUMN deleted_at;
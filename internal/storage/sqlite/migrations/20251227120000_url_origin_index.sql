@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS idx_url_origin ON url(origin);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_url_origin;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS alias_seq (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    value INTEGER NOT NULL
+);
+
+INSERT OR IGNORE INTO alias_seq (id, value) VALUES (1, 0);
+
+-- +goose Down
+DROP TABLE IF EXISTS alias_seq;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE url ADD COLUMN flagged_at DATETIME;
+ALTER TABLE url ADD COLUMN flag_reason TEXT;
+
+-- +goose Down
+ALTER TABLE url DROP COLUMN flag_reason;
+ALTER TABLE url DROP COLUMN flagged_at;
@@ -0,0 +1,856 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	msqlite "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+type Storage struct {
+	db *sql.DB
+}
+
+// insertRows caps the rows of a single multi-row INSERT, keeping it below
+// SQLite's limit on bound variables.
+const insertRows = 1000
+
+//go:embed migrations/*.sql
+var embedMigrations embed.FS
+
+var schemes = []string{"sqlite://", "sqlite3://"}
+
+// IsSQLite reports whether connStr points at an SQLite database,
+// i.e. uses the sqlite:// or sqlite3:// scheme.
+func IsSQLite(connStr string) bool {
+	for _, scheme := range schemes {
+		if strings.HasPrefix(connStr, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+func New(connStr string) (*Storage, error) {
+	const op = "storage.sqlite.New"
+
+	dsn := connStr
+	for _, scheme := range schemes {
+		dsn = strings.TrimPrefix(dsn, scheme)
+	}
+	if dsn == "" {
+		return nil, fmt.Errorf("%s: database path is empty", op)
+	}
+
+	// Store timestamps in a format SQLite's date functions understand.
+	if !strings.Contains(dsn, "_time_format=") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn += sep + "_time_format=sqlite"
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("%s: unable to open database: %w", op, err)
+	}
+
+	// SQLite allows a single writer at a time; serializing access through one
+	// connection avoids SQLITE_BUSY errors and keeps :memory: databases shared.
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("%s: unable to connect to database: %w", op, err)
+	}
+
+	if err := runMigrations(db); err != nil {
+		return nil, fmt.Errorf("%s: failed to apply migrations: %w", op, err)
+	}
+
+	return &Storage{db: db}, nil
+}
+
+func runMigrations(db *sql.DB) error {
+	goose.SetBaseFS(embedMigrations)
+
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		return fmt.Errorf("failed to set goose dialect: %w", err)
+	}
+
+	log.Println("Running migrations...")
+	if err := goose.Up(db, "migrations"); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	log.Println("Migrations applied successfully")
+	return nil
+}
+
+// utc normalizes timestamps before they are written: SQLite keeps them as
+// text, so only values in a single time zone compare correctly.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// scope returns the owner the caller of ctx is limited to as a query
+// argument. It is NULL for unrestricted callers, so queries check it with
+// `(?n IS NULL OR owner_id = ?n)`.
+func scope(ctx context.Context) sql.NullInt64 {
+	id, ok := storage.OwnerScope(ctx)
+	return sql.NullInt64{Int64: id, Valid: ok}
+}
+
+func isUniqueViolation(err error) bool {
+	var sqliteErr *msqlite.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY)
+}
+
+func (s *Storage) SaveURL(ctx context.Context, link storage.Link) error {
+	const op = "storage.sqlite.SaveURL"
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO url (alias, origin, host, created_at, expires_at, owner_id)
+		VALUES (?, ?, ?, ?, ?, NULLIF(?, 0));
+	`, link.Alias, link.URL, storage.HostOf(link.URL), link.CreatedAt.UTC(), utc(link.ExpiresAt), link.OwnerID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%s: %w", op, storage.ErrUrlExists)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveURLs inserts links in a single transaction and returns the aliases
+// that were stored. Links whose alias is already taken are skipped.
+func (s *Storage) SaveURLs(ctx context.Context, links []storage.Link) ([]string, error) {
+	const op = "storage.sqlite.SaveURLs"
+
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var saved []string
+	for start := 0; start < len(links); start += insertRows {
+		chunk := links[start:min(start+insertRows, len(links))]
+
+		values := make([]string, len(chunk))
+		args := make([]any, 0, len(chunk)*6)
+		for i, link := range chunk {
+			values[i] = "(?, ?, ?, ?, ?, NULLIF(?, 0))"
+			args = append(args, link.Alias, link.URL, storage.HostOf(link.URL), link.CreatedAt.UTC(), utc(link.ExpiresAt), link.OwnerID)
+		}
+
+		rows, err := tx.QueryContext(ctx, `
+			INSERT INTO url (alias, origin, host, created_at, expires_at, owner_id)
+			VALUES `+strings.Join(values, ", ")+`
+			ON CONFLICT (alias) DO NOTHING
+			RETURNING alias;
+		`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for rows.Next() {
+			var alias string
+			if err := rows.Scan(&alias); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			saved = append(saved, alias)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return saved, nil
+}
+
+func (s *Storage) GetURL(ctx context.Context, alias string) (storage.Link, error) {
+	const op = "storage.sqlite.GetURL"
+
+	link := storage.Link{Alias: alias}
+	var expiresAt, deletedAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, `
+		SELECT origin, expires_at, deleted_at
+		FROM url
+		WHERE alias = ?;
+	`, alias).Scan(&link.URL, &expiresAt, &deletedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+		}
+		return storage.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if deletedAt.Valid {
+		return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlDeleted)
+	}
+	if expiresAt.Valid {
+		link.ExpiresAt = &expiresAt.Time
+	}
+
+	if link.Expired(time.Now()) {
+		return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlExpired)
+	}
+
+	return link, nil
+}
+
+func (s *Storage) FindAlias(ctx context.Context, u string) (string, error) {
+	const op = "storage.sqlite.FindAlias"
+
+	var alias string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT alias
+		FROM url
+		WHERE origin = ?1 AND deleted_at IS NULL AND expires_at IS NULL
+			AND (?2 IS NULL OR owner_id = ?2)
+		ORDER BY id
+		LIMIT 1;
+	`, u, scope(ctx)).Scan(&alias)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return alias, nil
+}
+
+func (s *Storage) TakenAliases(ctx context.Context, aliases []string) ([]string, error) {
+	const op = "storage.sqlite.TakenAliases"
+
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(aliases))
+	for i, alias := range aliases {
+		args[i] = alias
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(aliases)), ",")
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT alias
+		FROM url
+		WHERE alias IN (`+placeholders+`);
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var taken []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		taken = append(taken, alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return taken, nil
+}
+
+func (s *Storage) DeleteURL(ctx context.Context, alias string) (string, error) {
+	const op = "storage.sqlite.DeleteURL"
+
+	var u string
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE url
+		SET deleted_at = ?1
+		WHERE alias = ?2 AND deleted_at IS NULL AND (?3 IS NULL OR owner_id = ?3)
+		RETURNING origin
+	`, time.Now().UTC(), alias, scope(ctx)).Scan(&u)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return u, nil
+}
+
+func (s *Storage) DeleteByOrigin(ctx context.Context, u string) ([]string, error) {
+	const op = "storage.sqlite.DeleteByOrigin"
+
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE url
+		SET deleted_at = ?1
+		WHERE origin = ?2 AND deleted_at IS NULL AND (?3 IS NULL OR owner_id = ?3)
+		RETURNING alias
+	`, time.Now().UTC(), u, scope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var aliases []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		aliases = append(aliases, alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(aliases) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	return aliases, nil
+}
+
+func (s *Storage) UpdateURL(ctx context.Context, upd storage.Update) (storage.Change, error) {
+	const op = "storage.sqlite.UpdateURL"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return storage.Change{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	change := storage.Change{
+		Alias:     upd.Alias,
+		ChangedBy: upd.ChangedBy,
+		ChangedAt: time.Now(),
+	}
+	var expiresAt sql.NullTime
+	err = tx.QueryRowContext(ctx, `
+		SELECT origin, expires_at
+		FROM url
+		WHERE alias = ?1 AND deleted_at IS NULL AND (?2 IS NULL OR owner_id = ?2);
+	`, upd.Alias, scope(ctx)).Scan(&change.OldURL, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.Change{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+		}
+		return storage.Change{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if expiresAt.Valid {
+		change.OldExpiresAt = &expiresAt.Time
+	}
+	change.NewURL, change.NewExpiresAt = upd.Apply(change.OldURL, change.OldExpiresAt)
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE url
+		SET origin = ?, host = ?, expires_at = ?
+		WHERE alias = ?;
+	`, change.NewURL, storage.HostOf(change.NewURL), utc(change.NewExpiresAt), upd.Alias)
+	if err != nil {
+		return storage.Change{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO url_history (alias, version, old_origin, new_origin, old_expires_at, new_expires_at, changed_by, changed_at)
+		SELECT ?1, COALESCE(MAX(version), 0) + 1, ?2, ?3, ?4, ?5, ?6, ?7
+		FROM url_history
+		WHERE alias = ?1
+		RETURNING version;
+	`, upd.Alias, change.OldURL, change.NewURL, utc(change.OldExpiresAt), utc(change.NewExpiresAt),
+		change.ChangedBy, change.ChangedAt.UTC()).Scan(&change.Version)
+	if err != nil {
+		return storage.Change{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storage.Change{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return change, nil
+}
+
+func (s *Storage) GetHistory(ctx context.Context, alias string) ([]storage.Change, error) {
+	const op = "storage.sqlite.GetHistory"
+
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM url WHERE alias = ?1 AND (?2 IS NULL OR owner_id = ?2));
+	`, alias, scope(ctx)).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT version, old_origin, new_origin, old_expires_at, new_expires_at, changed_by, changed_at
+		FROM url_history
+		WHERE alias = ?
+		ORDER BY version;
+	`, alias)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var history []storage.Change
+	for rows.Next() {
+		c := storage.Change{Alias: alias}
+		var oldExpiresAt, newExpiresAt sql.NullTime
+		if err := rows.Scan(&c.Version, &c.OldURL, &c.NewURL, &oldExpiresAt, &newExpiresAt, &c.ChangedBy, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if oldExpiresAt.Valid {
+			c.OldExpiresAt = &oldExpiresAt.Time
+		}
+		if newExpiresAt.Valid {
+			c.NewExpiresAt = &newExpiresAt.Time
+		}
+		history = append(history, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return history, nil
+}
+
+func (s *Storage) NextSequence(ctx context.Context) (int64, error) {
+	const op = "storage.sqlite.NextSequence"
+
+	var n int64
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE alias_seq
+		SET value = value + 1
+		WHERE id = 1
+		RETURNING value;
+	`).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
+
+func (s *Storage) SaveClicks(ctx context.Context, clicks []storage.Click) error {
+	const op = "storage.sqlite.SaveClicks"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO clicks (alias, clicked_at, referrer, user_agent, ip_hash)
+		VALUES (?, ?, ?, ?, ?);
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	for _, c := range clicks {
+		if _, err := stmt.ExecContext(ctx, c.Alias, c.ClickedAt.UTC(), c.Referrer, c.UserAgent, c.IPHash); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+var bucketFormats = map[storage.StatsInterval]string{
+	storage.IntervalHour: "%Y-%m-%dT%H:00:00Z",
+	storage.IntervalDay:  "%Y-%m-%dT00:00:00Z",
+}
+
+func (s *Storage) GetStats(ctx context.Context, q storage.StatsQuery) (storage.Stats, error) {
+	const op = "storage.sqlite.GetStats"
+
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM url WHERE alias = ?1 AND (?2 IS NULL OR owner_id = ?2));
+	`, q.Alias, scope(ctx)).Scan(&exists)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	from, to := q.From.UTC(), q.To.UTC()
+
+	var stats storage.Stats
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(DISTINCT ip_hash)
+		FROM clicks
+		WHERE alias = ? AND clicked_at >= ? AND clicked_at < ?;
+	`, q.Alias, from, to).Scan(&stats.TotalClicks, &stats.UniqueVisitors)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT strftime(?, clicked_at) AS bucket, COUNT(*), COUNT(DISTINCT ip_hash)
+		FROM clicks
+		WHERE alias = ? AND clicked_at >= ? AND clicked_at < ?
+		GROUP BY bucket
+		ORDER BY bucket;
+	`, bucketFormats[q.Interval], q.Alias, from, to)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			b     storage.StatsBucket
+			start string
+		)
+		if err := rows.Scan(&start, &b.Clicks, &b.UniqueVisitors); err != nil {
+			return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if b.Start, err = time.Parse(time.RFC3339, start); err != nil {
+			return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+		}
+		stats.Buckets = append(stats.Buckets, b)
+	}
+	if err := rows.Err(); err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats.TopReferrers, err = s.queryCounts(ctx, `
+		SELECT referrer, COUNT(*) AS clicks
+		FROM clicks
+		WHERE alias = ? AND clicked_at >= ? AND clicked_at < ? AND referrer <> ''
+		GROUP BY referrer
+		ORDER BY clicks DESC, referrer
+		LIMIT ?;
+	`, q.Alias, from, to, storage.TopReferrersLimit)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats.UserAgents, err = s.queryFamilies(ctx, `
+		SELECT user_agent, COUNT(*) AS clicks
+		FROM clicks
+		WHERE alias = ? AND clicked_at >= ? AND clicked_at < ?
+		GROUP BY user_agent;
+	`, q.Alias, from, to)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func (s *Storage) queryCounts(ctx context.Context, query string, args ...any) ([]storage.StatsCount, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []storage.StatsCount
+	for rows.Next() {
+		var c storage.StatsCount
+		if err := rows.Scan(&c.Value, &c.Clicks); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+// queryFamilies reads user agent counts like queryCounts, folding them into
+// browser families as they are scanned.
+func (s *Storage) queryFamilies(ctx context.Context, query string, args ...any) ([]storage.StatsCount, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	families := storage.FamilyCounts{}
+	for rows.Next() {
+		var (
+			userAgent string
+			clicks    int64
+		)
+		if err := rows.Scan(&userAgent, &clicks); err != nil {
+			return nil, err
+		}
+		families.Add(userAgent, clicks)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return families.Sorted(), nil
+}
+
+func (s *Storage) ListURLs(ctx context.Context, q storage.ListQuery) ([]storage.Link, error) {
+	const op = "storage.sqlite.ListURLs"
+
+	var (
+		where []string
+		args  []any
+	)
+
+	if q.Search != "" {
+		where = append(where, `origin LIKE '%' || ? || '%' ESCAPE '\'`)
+		args = append(args, storage.EscapeLike(q.Search))
+	}
+	if q.Host != "" {
+		where = append(where, "host = ?")
+		args = append(args, q.Host)
+	}
+	if q.Trashed {
+		where = append(where, "deleted_at IS NOT NULL")
+	} else {
+		where = append(where, "deleted_at IS NULL")
+	}
+	if owner, ok := storage.OwnerScope(ctx); ok {
+		where = append(where, "owner_id = ?")
+		args = append(args, owner)
+	}
+
+	cmp, dir := "<", "DESC"
+	if q.Order == storage.OrderAsc {
+		cmp, dir = ">", "ASC"
+	}
+	if q.After != nil {
+		where = append(where, fmt.Sprintf("(created_at, id) %s (?, ?)", cmp))
+		args = append(args, q.After.CreatedAt.UTC(), q.After.ID)
+	}
+
+	query := "SELECT id, alias, origin, created_at, expires_at, deleted_at, flagged_at, flag_reason, COALESCE(owner_id, 0) FROM url WHERE " + strings.Join(where, " AND ")
+	query += fmt.Sprintf(" ORDER BY created_at %s, id %s LIMIT ?", dir, dir)
+	args = append(args, q.Limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var links []storage.Link
+	for rows.Next() {
+		var (
+			link                            storage.Link
+			expiresAt, deletedAt, flaggedAt sql.NullTime
+			flagReason                      sql.NullString
+		)
+		if err := rows.Scan(&link.ID, &link.Alias, &link.URL, &link.CreatedAt, &expiresAt, &deletedAt, &flaggedAt, &flagReason, &link.OwnerID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if expiresAt.Valid {
+			link.ExpiresAt = &expiresAt.Time
+		}
+		if deletedAt.Valid {
+			link.DeletedAt = &deletedAt.Time
+		}
+		if flaggedAt.Valid {
+			link.FlaggedAt = &flaggedAt.Time
+			link.FlagReason = flagReason.String
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return links, nil
+}
+
+func (s *Storage) FlagURL(ctx context.Context, alias, reason string) error {
+	const op = "storage.sqlite.FlagURL"
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE url
+		SET flagged_at = ?, flag_reason = ?
+		WHERE alias = ? AND flagged_at IS NULL;
+	`, time.Now().UTC(), reason, alias)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) RestoreURL(ctx context.Context, alias string) (storage.Link, error) {
+	const op = "storage.sqlite.RestoreURL"
+
+	link := storage.Link{Alias: alias}
+	var expiresAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE url
+		SET deleted_at = NULL
+		WHERE alias = ?1 AND deleted_at IS NOT NULL AND (?2 IS NULL OR owner_id = ?2)
+		RETURNING id, origin, created_at, expires_at, COALESCE(owner_id, 0)
+	`, alias, scope(ctx)).Scan(&link.ID, &link.URL, &link.CreatedAt, &expiresAt, &link.OwnerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.Link{}, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+		}
+		return storage.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if expiresAt.Valid {
+		link.ExpiresAt = &expiresAt.Time
+	}
+
+	return link, nil
+}
+
+func (s *Storage) PurgeDeleted(ctx context.Context, before time.Time) ([]string, error) {
+	const op = "storage.sqlite.PurgeDeleted"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"url_history", "clicks"} {
+		_, err := tx.ExecContext(ctx, `
+			DELETE FROM `+table+`
+			WHERE alias IN (SELECT alias FROM url WHERE deleted_at < ?);
+		`, before.UTC())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		DELETE FROM url
+		WHERE deleted_at < ?
+		RETURNING alias
+	`, before.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var aliases []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		aliases = append(aliases, alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows.Close()
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return aliases, nil
+}
+
+func (s *Storage) FoldAliases(ctx context.Context) error {
+	const op = "storage.sqlite.FoldAliases"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	conflicts, err := caseConflicts(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%s: %w: %s", op, storage.ErrAliasCaseConflict, strings.Join(conflicts, ", "))
+	}
+
+	for _, table := range []string{"url_history", "clicks", "url"} {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE `+table+`
+			SET alias = lower(alias)
+			WHERE alias <> lower(alias);
+		`)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_url_alias_lower ON url(lower(alias));
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// caseConflicts returns up to ten aliases, lower-cased, that are stored in
+// more than one case.
+func caseConflicts(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT lower(alias)
+		FROM url
+		GROUP BY lower(alias)
+		HAVING COUNT(*) > 1
+		ORDER BY 1
+		LIMIT 10;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var conflicts []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, alias)
+	}
+
+	return conflicts, rows.Err()
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/useragent"
+)
+
+type StatsInterval string
+
+const (
+	IntervalHour StatsInterval = "hour"
+	IntervalDay  StatsInterval = "day"
+)
+
+func (i StatsInterval) Duration() time.Duration {
+	if i == IntervalHour {
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
+
+type StatsQuery struct {
+	Alias    string
+	From     time.Time
+	To       time.Time
+	Interval StatsInterval
+}
+
+type Stats struct {
+	TotalClicks    int64         `json:"total_clicks"`
+	UniqueVisitors int64         `json:"unique_visitors"`
+	Buckets        []StatsBucket `json:"buckets"`
+	TopReferrers   []StatsCount  `json:"top_referrers"`
+	UserAgents     []StatsCount  `json:"user_agents"`
+}
+
+type StatsBucket struct {
+	Start          time.Time `json:"start"`
+	Clicks         int64     `json:"clicks"`
+	UniqueVisitors int64     `json:"unique_visitors"`
+}
+
+type StatsCount struct {
+	Value  string `json:"value"`
+	Clicks int64  `json:"clicks"`
+}
+
+const TopReferrersLimit = 10
+
+// GetStats aggregates the clicks of an alias over [q.From, q.To). Buckets
+// with no clicks are filled in with zeros.
+func (s *UrlStorage) GetStats(ctx context.Context, q StatsQuery) (Stats, error) {
+	q.Alias = s.Key(q.Alias)
+	q.From = q.From.UTC().Truncate(q.Interval.Duration())
+	q.To = q.To.UTC()
+
+	stats, err := s.service.GetStats(ctx, q)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats.Buckets = fillBuckets(stats.Buckets, q)
+	if stats.UserAgents == nil {
+		stats.UserAgents = []StatsCount{}
+	}
+	if stats.TopReferrers == nil {
+		stats.TopReferrers = []StatsCount{}
+	}
+
+	return stats, nil
+}
+
+func fillBuckets(buckets []StatsBucket, q StatsQuery) []StatsBucket {
+	byStart := make(map[int64]StatsBucket, len(buckets))
+	for _, b := range buckets {
+		byStart[b.Start.Unix()] = b
+	}
+
+	step := q.Interval.Duration()
+	filled := []StatsBucket{}
+	for start := q.From; start.Before(q.To); start = start.Add(step) {
+		b, ok := byStart[start.Unix()]
+		if !ok {
+			b = StatsBucket{}
+		}
+		b.Start = start
+		filled = append(filled, b)
+	}
+
+	return filled
+}
+
+// FamilyCounts folds the clicks of raw user agents into browser families.
+// Backends feed it every distinct user agent as they read them, so that
+// no raw user agent has to be kept and none is left out of its family.
+type FamilyCounts map[string]int64
+
+func (c FamilyCounts) Add(userAgent string, clicks int64) {
+	c[useragent.Family(userAgent)] += clicks
+}
+
+// Sorted returns the families, most clicked first.
+func (c FamilyCounts) Sorted() []StatsCount {
+	families := make([]StatsCount, 0, len(c))
+	for family, clicks := range c {
+		families = append(families, StatsCount{Value: family, Clicks: clicks})
+	}
+	sort.Slice(families, func(i, j int) bool {
+		if families[i].Clicks != families[j].Clicks {
+			return families[i].Clicks > families[j].Clicks
+		}
+		return families[i].Value < families[j].Value
+	})
+
+	return families
+}
@@ -4,53 +4,250 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
+	"time"
 )
 
 type UrlStorage struct {
-	service UrlService
-	cache   CacheClient
-	log     *slog.Logger
+	service   UrlService
+	cache     CacheClient
+	log       *slog.Logger
+	foldAlias bool
+}
+
+type Link struct {
+	ID        int64      `json:"-"`
+	Alias     string     `json:"alias"`
+	URL       string     `json:"url"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// FlaggedAt is set when the destination was first found on the
+	// blocklist; FlagReason tells which list matched.
+	FlaggedAt  *time.Time `json:"flagged_at,omitempty"`
+	FlagReason string     `json:"flag_reason,omitempty"`
+	// OwnerID is the user who created the link, or 0 for links created
+	// before users existed and by the CLI.
+	OwnerID int64 `json:"owner_id,omitempty"`
+}
+
+// Expired reports whether the link's lifetime has ended at the given moment.
+func (l Link) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// Update describes a change to an existing alias. Empty URL and nil
+// ExpiresAt leave the current values untouched; ClearExpiresAt makes the
+// link permanent again.
+type Update struct {
+	Alias          string
+	URL            string
+	ExpiresAt      *time.Time
+	ClearExpiresAt bool
+	ChangedBy      string
+}
+
+// Change is an immutable history record of a single update of an alias.
+// Versions start at 1 for the first update; version 0 denotes the state
+// the alias was created with.
+type Change struct {
+	Version      int64      `json:"version"`
+	Alias        string     `json:"-"`
+	OldURL       string     `json:"old_url"`
+	NewURL       string     `json:"new_url"`
+	OldExpiresAt *time.Time `json:"old_expires_at,omitempty"`
+	NewExpiresAt *time.Time `json:"new_expires_at,omitempty"`
+	ChangedBy    string     `json:"changed_by,omitempty"`
+	ChangedAt    time.Time  `json:"changed_at"`
+}
+
+// Apply returns the destination and expiration resulting from applying the
+// update on top of the given values.
+func (u Update) Apply(origin string, expiresAt *time.Time) (string, *time.Time) {
+	if u.URL != "" {
+		origin = u.URL
+	}
+	if u.ExpiresAt != nil {
+		expiresAt = u.ExpiresAt
+	}
+	if u.ClearExpiresAt {
+		expiresAt = nil
+	}
+
+	return origin, expiresAt
+}
+
+type Click struct {
+	Alias     string
+	ClickedAt time.Time
+	Referrer  string
+	UserAgent string
+	IPHash    string
 }
 
 type UrlService interface {
-	SaveURL(ctx context.Context, originalURL, alias string) error
-	GetURL(ctx context.Context, alias string) (string, error)
+	SaveURL(ctx context.Context, link Link) error
+	SaveURLs(ctx context.Context, links []Link) ([]string, error)
+	GetURL(ctx context.Context, alias string) (Link, error)
+	FindAlias(ctx context.Context, u string) (string, error)
+	TakenAliases(ctx context.Context, aliases []string) ([]string, error)
 	DeleteURL(ctx context.Context, alias string) (string, error)
+	DeleteByOrigin(ctx context.Context, u string) ([]string, error)
+	UpdateURL(ctx context.Context, upd Update) (Change, error)
+	GetHistory(ctx context.Context, alias string) ([]Change, error)
+	NextSequence(ctx context.Context) (int64, error)
+	SaveClicks(ctx context.Context, clicks []Click) error
+	GetStats(ctx context.Context, q StatsQuery) (Stats, error)
+	ListURLs(ctx context.Context, q ListQuery) ([]Link, error)
+	RestoreURL(ctx context.Context, alias string) (Link, error)
+	FlagURL(ctx context.Context, alias, reason string) error
+	PurgeDeleted(ctx context.Context, before time.Time) ([]string, error)
+	// FoldAliases prepares stored links for case-insensitive aliases: it
+	// lower-cases the aliases of existing links and their history and
+	// clicks, and enforces that aliases are unique regardless of case. It
+	// fails with ErrAliasCaseConflict, changing nothing, if aliases exist
+	// that differ only in case.
+	FoldAliases(ctx context.Context) error
 }
 
 type CacheClient interface {
-	SetURL(ctx context.Context, u, alias string) error
+	SetURL(ctx context.Context, u, alias string, expiresAt *time.Time) error
 	GetURL(ctx context.Context, alias string) (string, error)
+	GetAlias(ctx context.Context, u string) (string, error)
 	DelURL(ctx context.Context, u, alias string) error
 }
 
-func New(log *slog.Logger, service UrlService, cache CacheClient) *UrlStorage {
-	return &UrlStorage{
+type Option func(*UrlStorage)
+
+// WithCaseInsensitiveAliases folds every alias to lower case before it
+// reaches the service or the cache. Aliases are then stored in their
+// normalized form, so the unique alias index rejects aliases differing only
+// in case. The service must have folded the aliases stored before the
+// option was enabled, see UrlService.FoldAliases.
+func WithCaseInsensitiveAliases() Option {
+	return func(s *UrlStorage) {
+		s.foldAlias = true
+	}
+}
+
+func New(log *slog.Logger, service UrlService, cache CacheClient, opts ...Option) *UrlStorage {
+	s := &UrlStorage{
 		service: service,
 		cache:   cache,
 		log:     log,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
-func (s *UrlStorage) SaveURL(ctx context.Context, u, alias string) error {
-	if err := s.service.SaveURL(ctx, u, alias); err != nil {
+// Key returns the form under which an alias is stored.
+func (s *UrlStorage) Key(alias string) string {
+	if s.foldAlias {
+		return strings.ToLower(alias)
+	}
+	return alias
+}
+
+func (s *UrlStorage) SaveURL(ctx context.Context, link Link) error {
+	link.Alias = s.Key(link.Alias)
+	link.OwnerID = owner(ctx, link)
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+
+	if err := s.service.SaveURL(ctx, link); err != nil {
 		return err
 	}
 
 	if s.cache != nil {
-		s.log.Info("caching URL", slog.String("alias", alias))
-		err := s.cache.SetURL(ctx, u, alias)
+		s.log.Info("caching URL", slog.String("alias", link.Alias))
+		err := s.cache.SetURL(ctx, link.URL, link.Alias, link.ExpiresAt)
 		if err != nil {
-			s.log.Warn("failed to cache URL", slog.String("alias", alias), slog.Any("err", err.Error()))
+			s.log.Warn("failed to cache URL", slog.String("alias", link.Alias), slog.Any("err", err.Error()))
 		} else {
-			s.log.Info("URL cached", slog.String("alias", alias))
+			s.log.Info("URL cached", slog.String("alias", link.Alias))
 		}
 	}
 
 	return nil
 }
 
+// SaveBatchSize is the number of links SaveURLs stores per transaction.
+const SaveBatchSize = 500
+
+// SaveURLs stores links in transactions of SaveBatchSize and reports the
+// outcome of each one at its index. A link whose alias is taken, including
+// by an earlier link of the same call, fails with ErrUrlExists; when a
+// transaction fails, every link in it gets that error.
+func (s *UrlStorage) SaveURLs(ctx context.Context, links []Link) []error {
+	errs := make([]error, len(links))
+	now := time.Now()
+
+	var pending []int
+	seen := make(map[string]struct{}, len(links))
+	batch := make([]Link, len(links))
+	for i, link := range links {
+		link.Alias = s.Key(link.Alias)
+		link.OwnerID = owner(ctx, link)
+		if link.CreatedAt.IsZero() {
+			link.CreatedAt = now
+		}
+		batch[i] = link
+
+		if _, ok := seen[link.Alias]; ok {
+			errs[i] = ErrUrlExists
+			continue
+		}
+		seen[link.Alias] = struct{}{}
+		pending = append(pending, i)
+	}
+
+	for start := 0; start < len(pending); start += SaveBatchSize {
+		idx := pending[start:min(start+SaveBatchSize, len(pending))]
+
+		chunk := make([]Link, len(idx))
+		for j, i := range idx {
+			chunk[j] = batch[i]
+		}
+
+		saved, err := s.service.SaveURLs(ctx, chunk)
+		if err != nil {
+			s.log.Error("failed to save batch", slog.Int("size", len(chunk)), slog.Any("err", err.Error()))
+			for _, i := range idx {
+				errs[i] = err
+			}
+			continue
+		}
+
+		stored := make(map[string]struct{}, len(saved))
+		for _, alias := range saved {
+			stored[alias] = struct{}{}
+		}
+
+		for _, i := range idx {
+			link := batch[i]
+			if _, ok := stored[link.Alias]; !ok {
+				errs[i] = ErrUrlExists
+				continue
+			}
+
+			if s.cache != nil {
+				if err := s.cache.SetURL(ctx, link.URL, link.Alias, link.ExpiresAt); err != nil {
+					s.log.Warn("failed to cache URL", slog.String("alias", link.Alias), slog.Any("err", err.Error()))
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
 func (s *UrlStorage) GetURL(ctx context.Context, alias string) (string, error) {
+	alias = s.Key(alias)
+
 	if s.cache != nil {
 		s.log.Info("checking cache for URL", slog.String("alias", alias))
 		u, err := s.cache.GetURL(ctx, alias)
@@ -62,14 +259,14 @@ func (s *UrlStorage) GetURL(ctx context.Context, alias string) (string, error) {
 		}
 	}
 
-	u, err := s.service.GetURL(ctx, alias)
+	link, err := s.service.GetURL(ctx, alias)
 	if err != nil {
 		return "", err
 	}
 
 	if s.cache != nil {
 		s.log.Info("caching URL", slog.String("alias", alias))
-		err := s.cache.SetURL(ctx, u, alias)
+		err := s.cache.SetURL(ctx, link.URL, alias, link.ExpiresAt)
 		if err != nil {
 			s.log.Warn("failed to cache URL", slog.String("alias", alias), slog.Any("err", err.Error()))
 		} else {
@@ -77,10 +274,70 @@ func (s *UrlStorage) GetURL(ctx context.Context, alias string) (string, error) {
 		}
 	}
 
-	return u, nil
+	return link.URL, nil
+}
+
+// FindAlias returns an alias of a live, non-expiring link pointing at u.
+// The reverse index in the cache is consulted first; on a miss the service
+// is queried and the result is cached. The reverse index does not know who
+// owns an alias, so callers limited to their own links skip it.
+func (s *UrlStorage) FindAlias(ctx context.Context, u string) (string, error) {
+	if _, scoped := OwnerScope(ctx); s.cache != nil && !scoped {
+		alias, err := s.cache.GetAlias(ctx, u)
+		if err == nil {
+			s.log.Info("alias found in cache", slog.String("alias", alias))
+			return alias, nil
+		}
+	}
+
+	alias, err := s.service.FindAlias(ctx, u)
+	if err != nil {
+		return "", err
+	}
+
+	if s.cache != nil {
+		s.log.Info("caching URL", slog.String("alias", alias))
+		err := s.cache.SetURL(ctx, u, alias, nil)
+		if err != nil {
+			s.log.Warn("failed to cache URL", slog.String("alias", alias), slog.Any("err", err.Error()))
+		} else {
+			s.log.Info("URL cached", slog.String("alias", alias))
+		}
+	}
+
+	return alias, nil
+}
+
+// TakenAliases returns those of the given aliases that are already in use,
+// in the form they were passed in. Trashed links keep their alias until
+// they are purged, so their aliases are reported as taken too.
+func (s *UrlStorage) TakenAliases(ctx context.Context, aliases []string) ([]string, error) {
+	byKey := make(map[string][]string, len(aliases))
+	keys := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		key := s.Key(alias)
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], alias)
+	}
+
+	takenKeys, err := s.service.TakenAliases(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var taken []string
+	for _, key := range takenKeys {
+		taken = append(taken, byKey[key]...)
+	}
+
+	return taken, nil
 }
 
 func (s *UrlStorage) DeleteURL(ctx context.Context, alias string) (string, error) {
+	alias = s.Key(alias)
+
 	u, err := s.service.DeleteURL(ctx, alias)
 	if err != nil {
 		return "", err
@@ -99,7 +356,138 @@ func (s *UrlStorage) DeleteURL(ctx context.Context, alias string) (string, error
 	return u, nil
 }
 
+func (s *UrlStorage) DeleteByOrigin(ctx context.Context, u string) ([]string, error) {
+	aliases, err := s.service.DeleteByOrigin(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		for _, alias := range aliases {
+			s.log.Info("deleting URL from cache", slog.String("alias", alias))
+			err := s.cache.DelURL(ctx, u, alias)
+			if err != nil {
+				s.log.Warn("failed to delete URL from cache", slog.String("alias", alias), slog.Any("err", err.Error()))
+			} else {
+				s.log.Info("URL deleted from cache", slog.String("alias", alias))
+			}
+		}
+	}
+
+	return aliases, nil
+}
+
+// UpdateURL applies upd and returns the recorded history entry. Both the
+// alias entry and the reverse index of the old destination are evicted from
+// the cache, so the next redirect reads the new state from storage.
+func (s *UrlStorage) UpdateURL(ctx context.Context, upd Update) (Change, error) {
+	upd.Alias = s.Key(upd.Alias)
+
+	change, err := s.service.UpdateURL(ctx, upd)
+	if err != nil {
+		return Change{}, err
+	}
+
+	if s.cache != nil {
+		s.log.Info("deleting URL from cache", slog.String("alias", upd.Alias))
+		err := s.cache.DelURL(ctx, change.OldURL, upd.Alias)
+		if err != nil {
+			s.log.Warn("failed to delete URL from cache", slog.String("alias", upd.Alias), slog.Any("err", err.Error()))
+		} else {
+			s.log.Info("URL deleted from cache", slog.String("alias", upd.Alias))
+		}
+	}
+
+	return change, nil
+}
+
+func (s *UrlStorage) GetHistory(ctx context.Context, alias string) ([]Change, error) {
+	return s.service.GetHistory(ctx, s.Key(alias))
+}
+
+// Version returns the destination and expiration an alias had at the
+// given version. Version 0 is the link as it was first saved, which is the
+// current link while it has never been updated.
+func (s *UrlStorage) Version(ctx context.Context, alias string, version int64) (Link, error) {
+	alias = s.Key(alias)
+
+	history, err := s.service.GetHistory(ctx, alias)
+	if err != nil {
+		return Link{}, err
+	}
+	if version == 0 && len(history) == 0 {
+		return s.service.GetURL(ctx, alias)
+	}
+
+	for _, c := range history {
+		if version == 0 && c.Version == 1 {
+			return Link{Alias: alias, URL: c.OldURL, ExpiresAt: c.OldExpiresAt}, nil
+		}
+		if c.Version == version {
+			return Link{Alias: alias, URL: c.NewURL, ExpiresAt: c.NewExpiresAt}, nil
+		}
+	}
+
+	return Link{}, ErrVersionNotFound
+}
+
+// RestoreURL takes a link out of the trash. Deleted links are evicted from
+// the cache, so there is nothing to invalidate here.
+func (s *UrlStorage) RestoreURL(ctx context.Context, alias string) (Link, error) {
+	return s.service.RestoreURL(ctx, s.Key(alias))
+}
+
+// FlagURL records that the destination of an alias was found on the
+// blocklist. Only the first flag is kept.
+func (s *UrlStorage) FlagURL(ctx context.Context, alias, reason string) error {
+	return s.service.FlagURL(ctx, s.Key(alias), reason)
+}
+
+// PurgeDeleted permanently removes links that were moved to the trash
+// before the given moment, together with their history and clicks.
+func (s *UrlStorage) PurgeDeleted(ctx context.Context, before time.Time) ([]string, error) {
+	return s.service.PurgeDeleted(ctx, before)
+}
+
+// NextSequence returns the next value of the alias sequence.
+func (s *UrlStorage) NextSequence(ctx context.Context) (int64, error) {
+	return s.service.NextSequence(ctx)
+}
+
+func (s *UrlStorage) SaveClicks(ctx context.Context, clicks []Click) error {
+	for i := range clicks {
+		clicks[i].Alias = s.Key(clicks[i].Alias)
+	}
+
+	return s.service.SaveClicks(ctx, clicks)
+}
+
+// CacheTTL caps a cache entry TTL at the remaining lifetime of a link so
+// that the cache never outlives it. It returns false if the link has
+// already expired and must not be cached at all.
+func CacheTTL(ttl time.Duration, expiresAt *time.Time) (time.Duration, bool) {
+	if expiresAt == nil {
+		return ttl, true
+	}
+
+	remaining := time.Until(*expiresAt)
+	if remaining <= 0 {
+		return 0, false
+	}
+	if ttl <= 0 || remaining < ttl {
+		return remaining, true
+	}
+
+	return ttl, true
+}
+
 var (
 	ErrUrlNotFound = errors.New("URL not found")
 	ErrUrlExists   = errors.New("URL already exists")
+	ErrUrlExpired  = errors.New("URL expired")
+	ErrUrlDeleted  = errors.New("URL deleted")
+
+	ErrVersionNotFound = errors.New("version not found")
+
+	ErrAliasCaseConflict = errors.New("aliases differ only in case")
 )
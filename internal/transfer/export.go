@@ -0,0 +1,112 @@
+package transfer
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+// exportPage is the number of links read from storage at a time.
+const exportPage = 500
+
+// columns is the header of exported CSV files.
+var columns = []string{"alias", "url", "created_at", "expires_at", "flagged_at", "flag_reason"}
+
+// Export streams every live link, oldest first, to w and returns how many
+// were written. Expired links are included; trashed ones are not.
+func (t *Transfer) Export(ctx context.Context, w io.Writer, format Format) (int, error) {
+	const op = "transfer.Export"
+
+	enc, err := newEncoder(w, format)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count := 0
+	q := storage.ListQuery{Limit: exportPage, Order: storage.OrderAsc}
+	for {
+		page, err := t.store.ListURLs(ctx, q)
+		if err != nil {
+			return count, fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, link := range page.Links {
+			if err := enc.encode(link); err != nil {
+				return count, fmt.Errorf("%s: %w", op, err)
+			}
+			count++
+		}
+		if err := enc.flush(); err != nil {
+			return count, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if page.Next == nil {
+			return count, nil
+		}
+		q.After = page.Next
+	}
+}
+
+type encoder interface {
+	encode(link storage.Link) error
+	flush() error
+}
+
+func newEncoder(w io.Writer, format Format) (encoder, error) {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return nil, err
+		}
+		return &csvEncoder{w: cw}, nil
+	case FormatNDJSON:
+		return &jsonEncoder{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("%w %q", ErrFormat, format)
+	}
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) encode(link storage.Link) error {
+	return e.w.Write([]string{
+		link.Alias,
+		link.URL,
+		formatTime(&link.CreatedAt),
+		formatTime(link.ExpiresAt),
+		formatTime(link.FlaggedAt),
+		link.FlagReason,
+	})
+}
+
+func (e *csvEncoder) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *jsonEncoder) encode(link storage.Link) error {
+	return e.enc.Encode(link)
+}
+
+func (e *jsonEncoder) flush() error {
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,417 @@
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+// maxLine bounds a single NDJSON line.
+const maxLine = 1 << 20
+
+// changedBy is recorded in the history of links overwritten by an import.
+const changedBy = "import"
+
+type ImportOptions struct {
+	Strategy Strategy
+	// DryRun reports what the import would do without writing anything.
+	DryRun bool
+	// MaxRecords caps the records of the imported file; zero means no cap.
+	MaxRecords int
+}
+
+// Report sums up an import. Every record ends up in exactly one of
+// Created, Updated, Skipped and Failed.
+type Report struct {
+	Total     int           `json:"total"`
+	Created   int           `json:"created"`
+	Updated   int           `json:"updated"`
+	Skipped   int           `json:"skipped"`
+	Failed    int           `json:"failed"`
+	DryRun    bool          `json:"dry_run,omitempty"`
+	Conflicts []string      `json:"conflicts,omitempty"`
+	Errors    []RecordError `json:"errors,omitempty"`
+}
+
+// RecordError describes a record that could not be imported. Line is the
+// line of the record in the imported file.
+type RecordError struct {
+	Line  int    `json:"line"`
+	Alias string `json:"alias,omitempty"`
+	Error string `json:"error"`
+}
+
+// ParseError is returned when the imported file itself is malformed.
+// Nothing is written in that case.
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+type record struct {
+	line int
+	link storage.Link
+}
+
+// Import reads links from r and stores them. The whole file is parsed
+// before anything is written, so a malformed file imports nothing; invalid
+// records and destinations refused by the destination policy are reported
+// and skipped. Aliases are imported as they are, without applying the
+// alias policy. With StrategyFail a single taken alias aborts the import
+// with ErrConflict and the report lists all of them. A file with more than
+// MaxRecords records is refused with ErrTooLarge as soon as the record
+// past the cap is read.
+func (t *Transfer) Import(ctx context.Context, r io.Reader, format Format, opts ImportOptions) (Report, error) {
+	const op = "transfer.Import"
+
+	if opts.Strategy == "" {
+		opts.Strategy = StrategySkip
+	}
+	if _, err := ParseStrategy(string(opts.Strategy)); err != nil {
+		return Report{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	records, err := decode(r, format, opts.MaxRecords)
+	if err != nil {
+		return Report{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report := Report{Total: len(records), DryRun: opts.DryRun}
+
+	valid := make([]record, 0, len(records))
+	seen := make(map[string]int, len(records))
+	for _, rec := range records {
+		if err := validate(rec.link); err != nil {
+			report.fail(rec, err.Error())
+			continue
+		}
+		if rec.link.URL, err = t.vet(ctx, rec.link.URL); err != nil {
+			report.fail(rec, err.Error())
+			continue
+		}
+		if line, ok := seen[rec.link.Alias]; ok {
+			report.fail(rec, fmt.Sprintf("alias already used on line %d", line))
+			continue
+		}
+		seen[rec.link.Alias] = rec.line
+		valid = append(valid, rec)
+	}
+
+	taken, err := t.taken(ctx, valid)
+	if err != nil {
+		return Report{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var fresh, existing []record
+	for _, rec := range valid {
+		if _, ok := taken[rec.link.Alias]; ok {
+			existing = append(existing, rec)
+		} else {
+			fresh = append(fresh, rec)
+		}
+	}
+
+	if opts.Strategy == StrategyFail && len(existing) > 0 {
+		for _, rec := range existing {
+			report.Conflicts = append(report.Conflicts, rec.link.Alias)
+		}
+		report.Failed += len(valid)
+		return report, fmt.Errorf("%s: %w: %d", op, ErrConflict, len(existing))
+	}
+
+	if opts.DryRun {
+		report.Created += len(fresh)
+		if opts.Strategy == StrategyOverwrite {
+			report.Updated += len(existing)
+		} else {
+			report.Skipped += len(existing)
+		}
+		return report, nil
+	}
+
+	links := make([]storage.Link, len(fresh))
+	for i, rec := range fresh {
+		links[i] = rec.link
+	}
+	for i, err := range t.store.SaveURLs(ctx, links) {
+		rec := fresh[i]
+		switch {
+		case err == nil:
+			report.Created++
+			t.restoreFlag(ctx, rec, &report)
+		case errors.Is(err, storage.ErrUrlExists):
+			report.fail(rec, "alias already exists")
+		default:
+			report.fail(rec, err.Error())
+		}
+	}
+
+	for _, rec := range existing {
+		if opts.Strategy != StrategyOverwrite {
+			report.Skipped++
+			continue
+		}
+
+		_, err := t.store.UpdateURL(ctx, storage.Update{
+			Alias:          rec.link.Alias,
+			URL:            rec.link.URL,
+			ExpiresAt:      rec.link.ExpiresAt,
+			ClearExpiresAt: rec.link.ExpiresAt == nil,
+			ChangedBy:      changedBy,
+		})
+		switch {
+		case err == nil:
+			report.Updated++
+			t.restoreFlag(ctx, rec, &report)
+		case errors.Is(err, storage.ErrUrlNotFound):
+			report.fail(rec, t.unavailable(ctx, rec.link.Alias))
+		default:
+			report.fail(rec, err.Error())
+		}
+	}
+
+	return report, nil
+}
+
+func (r *Report) fail(rec record, msg string) {
+	r.Failed++
+	r.Errors = append(r.Errors, RecordError{Line: rec.line, Alias: rec.link.Alias, Error: msg})
+}
+
+// taken returns the aliases of records that are already in use.
+func (t *Transfer) taken(ctx context.Context, records []record) (map[string]struct{}, error) {
+	taken := make(map[string]struct{})
+	for start := 0; start < len(records); start += storage.SaveBatchSize {
+		chunk := records[start:min(start+storage.SaveBatchSize, len(records))]
+
+		aliases := make([]string, len(chunk))
+		for i, rec := range chunk {
+			aliases[i] = rec.link.Alias
+		}
+
+		found, err := t.store.TakenAliases(ctx, aliases)
+		if err != nil {
+			return nil, err
+		}
+		for _, alias := range found {
+			taken[alias] = struct{}{}
+		}
+	}
+
+	return taken, nil
+}
+
+// unavailable explains why a taken alias could not be overwritten. Taken
+// aliases are found regardless of their owner, but links can only be
+// updated by their owner and only while out of the trash. The history is
+// visible to the owner for trashed links too, which tells the cases apart.
+func (t *Transfer) unavailable(ctx context.Context, alias string) string {
+	if _, err := t.store.GetHistory(ctx, alias); errors.Is(err, storage.ErrUrlNotFound) {
+		return "alias is taken by a link of another user"
+	}
+	return "alias is taken by a link in the trash"
+}
+
+// restoreFlag carries the blocklist flag of an imported link over. The
+// flag gets the time of the import, not the one it was originally set at.
+func (t *Transfer) restoreFlag(ctx context.Context, rec record, report *Report) {
+	if rec.link.FlaggedAt == nil && rec.link.FlagReason == "" {
+		return
+	}
+
+	if err := t.store.FlagURL(ctx, rec.link.Alias, rec.link.FlagReason); err != nil {
+		report.Errors = append(report.Errors, RecordError{
+			Line:  rec.line,
+			Alias: rec.link.Alias,
+			Error: "link imported but its flag was not: " + err.Error(),
+		})
+	}
+}
+
+// vet runs an imported destination through the destination policy and
+// returns its canonical form.
+func (t *Transfer) vet(ctx context.Context, raw string) (string, error) {
+	if t.destinations != nil {
+		if err := t.destinations.Check(ctx, raw); err != nil {
+			return "", err
+		}
+	}
+
+	if t.canonicalizer != nil {
+		canonical, err := t.canonicalizer.Canonicalize(raw)
+		if err != nil {
+			return "", errors.New("url is not a valid URL")
+		}
+		return canonical, nil
+	}
+
+	return raw, nil
+}
+
+func validate(link storage.Link) error {
+	if link.Alias == "" {
+		return errors.New("alias is required")
+	}
+	if strings.ContainsAny(link.Alias, "/?#") || strings.TrimSpace(link.Alias) != link.Alias {
+		return errors.New("alias contains characters that are not allowed")
+	}
+
+	u, err := url.Parse(link.URL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return errors.New("url is not a valid URL")
+	}
+
+	return nil
+}
+
+func decode(r io.Reader, format Format, max int) ([]record, error) {
+	switch format {
+	case FormatCSV:
+		return decodeCSV(r, max)
+	case FormatNDJSON:
+		return decodeNDJSON(r, max)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrFormat, format)
+	}
+}
+
+// decodeCSV reads a CSV file whose first row names the columns. Only the
+// alias and url columns are required; origin is accepted in place of url,
+// and unknown columns are ignored.
+func decodeCSV(r io.Reader, max int) ([]record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &ParseError{Line: 1, Err: err}
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "origin" {
+			name = "url"
+		}
+		if _, ok := index[name]; !ok {
+			index[name] = i
+		}
+	}
+	for _, name := range []string{"alias", "url"} {
+		if _, ok := index[name]; !ok {
+			return nil, &ParseError{Line: 1, Err: fmt.Errorf("missing %s column", name)}
+		}
+	}
+
+	var records []record
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if max > 0 && len(records) >= max {
+			return nil, fmt.Errorf("%w: more than %d", ErrTooLarge, max)
+		}
+		if err != nil {
+			var csvErr *csv.ParseError
+			if errors.As(err, &csvErr) {
+				return nil, &ParseError{Line: csvErr.Line, Err: csvErr.Err}
+			}
+			return nil, &ParseError{Line: len(records) + 2, Err: err}
+		}
+		line, _ := cr.FieldPos(0)
+
+		field := func(name string) string {
+			i, ok := index[name]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+
+		link := storage.Link{
+			Alias:      field("alias"),
+			URL:        field("url"),
+			FlagReason: field("flag_reason"),
+		}
+		for name, dst := range map[string]**time.Time{"expires_at": &link.ExpiresAt, "flagged_at": &link.FlaggedAt} {
+			if *dst, err = parseTime(field(name)); err != nil {
+				return nil, &ParseError{Line: line, Err: fmt.Errorf("column %s: %w", name, err)}
+			}
+		}
+		created, err := parseTime(field("created_at"))
+		if err != nil {
+			return nil, &ParseError{Line: line, Err: fmt.Errorf("column created_at: %w", err)}
+		}
+		if created != nil {
+			link.CreatedAt = *created
+		}
+
+		records = append(records, record{line: line, link: link})
+	}
+}
+
+func decodeNDJSON(r io.Reader, max int) ([]record, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLine)
+
+	var records []record
+	line := 0
+	for sc.Scan() {
+		line++
+		raw := strings.TrimSpace(sc.Text())
+		if raw == "" {
+			continue
+		}
+		if max > 0 && len(records) >= max {
+			return nil, fmt.Errorf("%w: more than %d", ErrTooLarge, max)
+		}
+
+		var link storage.Link
+		if err := json.Unmarshal([]byte(raw), &link); err != nil {
+			return nil, &ParseError{Line: line, Err: err}
+		}
+		// User ids do not carry over between databases, so imported links
+		// belong to whoever imports them.
+		link.ID, link.DeletedAt, link.OwnerID = 0, nil, 0
+
+		records = append(records, record{line: line, link: link})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, &ParseError{Line: line + 1, Err: err}
+	}
+
+	return records, nil
+}
+
+func parseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, errors.New("not an RFC 3339 timestamp")
+	}
+	return &t, nil
+}
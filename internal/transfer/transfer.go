@@ -0,0 +1,100 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+// ContentType returns the media type files of the format are served as.
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// Strategy decides what happens to imported links whose alias is taken.
+type Strategy string
+
+const (
+	// StrategySkip leaves the existing link untouched.
+	StrategySkip Strategy = "skip"
+	// StrategyOverwrite points the existing link at the imported origin
+	// and expiration, recording the change in its history.
+	StrategyOverwrite Strategy = "overwrite"
+	// StrategyFail aborts the import before anything is written.
+	StrategyFail Strategy = "fail"
+)
+
+var (
+	ErrFormat   = errors.New("unknown format")
+	ErrStrategy = errors.New("unknown conflict strategy")
+	ErrConflict = errors.New("aliases already exist")
+	ErrTooLarge = errors.New("too many records")
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatCSV, FormatNDJSON:
+		return f, nil
+	case "":
+		return FormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w %q", ErrFormat, s)
+	}
+}
+
+func ParseStrategy(s string) (Strategy, error) {
+	switch st := Strategy(s); st {
+	case StrategySkip, StrategyOverwrite, StrategyFail:
+		return st, nil
+	case "":
+		return StrategySkip, nil
+	default:
+		return "", fmt.Errorf("%w %q", ErrStrategy, s)
+	}
+}
+
+// Store is the part of storage the transfer is built on.
+type Store interface {
+	ListURLs(ctx context.Context, q storage.ListQuery) (storage.ListPage, error)
+	TakenAliases(ctx context.Context, aliases []string) ([]string, error)
+	SaveURLs(ctx context.Context, links []storage.Link) []error
+	UpdateURL(ctx context.Context, upd storage.Update) (storage.Change, error)
+	GetHistory(ctx context.Context, alias string) ([]storage.Change, error)
+	FlagURL(ctx context.Context, alias, reason string) error
+}
+
+type UrlCanonicalizer interface {
+	Canonicalize(raw string) (string, error)
+}
+
+type DestinationPolicy interface {
+	Check(ctx context.Context, raw string) error
+}
+
+// Transfer exports the link database to portable files and imports such
+// files back, e.g. from another shortener.
+type Transfer struct {
+	store         Store
+	canonicalizer UrlCanonicalizer
+	destinations  DestinationPolicy
+}
+
+// New returns a Transfer over store. Imported destinations go through the
+// same canonicalizer and destination policy as links saved over the API; a
+// nil canonicalizer imports them as they are, and a nil destination policy
+// accepts any of them.
+func New(store Store, canonicalizer UrlCanonicalizer, destinations DestinationPolicy) *Transfer {
+	return &Transfer{store: store, canonicalizer: canonicalizer, destinations: destinations}
+}
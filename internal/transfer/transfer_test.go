@@ -0,0 +1,269 @@
+package transfer_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/destination"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage"
+	"github.com/n0f4ph4mst3r/goshort/internal/storage/memory"
+	"github.com/n0f4ph4mst3r/goshort/internal/transfer"
+	"github.com/n0f4ph4mst3r/goshort/internal/urlnorm"
+)
+
+func newStore(t *testing.T, links ...storage.Link) *storage.UrlStorage {
+	t.Helper()
+
+	store := storage.New(sldiscard.NewDiscardLogger(), memory.New(), nil)
+	for _, link := range links {
+		require.NoError(t, store.SaveURL(context.Background(), link))
+	}
+	return store
+}
+
+func TestTransfer_RoundTrip(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, format := range []transfer.Format{transfer.FormatCSV, transfer.FormatNDJSON} {
+		format := format
+
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			src := newStore(t,
+				storage.Link{Alias: "first", URL: "https://example.com/a,b", CreatedAt: created},
+				storage.Link{Alias: "second", URL: "https://example.org", CreatedAt: created.Add(time.Hour), ExpiresAt: &expires},
+			)
+			require.NoError(t, src.FlagURL(context.Background(), "second", "destination domain is on the blocklist"))
+
+			var buf bytes.Buffer
+			n, err := transfer.New(src, nil, nil).Export(context.Background(), &buf, format)
+			require.NoError(t, err)
+			require.Equal(t, 2, n)
+
+			dst := newStore(t)
+			report, err := transfer.New(dst, nil, nil).Import(context.Background(), &buf, format, transfer.ImportOptions{})
+			require.NoError(t, err)
+			require.Equal(t, transfer.Report{Total: 2, Created: 2}, report)
+
+			page, err := dst.ListURLs(context.Background(), storage.ListQuery{Limit: 10, Order: storage.OrderAsc})
+			require.NoError(t, err)
+			require.Len(t, page.Links, 2)
+			require.Equal(t, "first", page.Links[0].Alias)
+			require.Equal(t, "https://example.com/a,b", page.Links[0].URL)
+			require.True(t, created.Equal(page.Links[0].CreatedAt))
+			require.Nil(t, page.Links[0].FlaggedAt)
+			require.Equal(t, "second", page.Links[1].Alias)
+			require.True(t, expires.Equal(*page.Links[1].ExpiresAt))
+			require.Equal(t, "destination domain is on the blocklist", page.Links[1].FlagReason)
+		})
+	}
+}
+
+func TestTransfer_ImportStrategies(t *testing.T) {
+	const input = "alias,origin\nexisting,https://new.example\nfresh,https://fresh.example\n"
+
+	cases := []struct {
+		name     string
+		opts     transfer.ImportOptions
+		report   transfer.Report
+		err      error
+		existing string
+		fresh    bool
+	}{
+		{
+			name:     "Skip",
+			opts:     transfer.ImportOptions{Strategy: transfer.StrategySkip},
+			report:   transfer.Report{Total: 2, Created: 1, Skipped: 1},
+			existing: "https://old.example",
+			fresh:    true,
+		},
+		{
+			name:     "Overwrite",
+			opts:     transfer.ImportOptions{Strategy: transfer.StrategyOverwrite},
+			report:   transfer.Report{Total: 2, Created: 1, Updated: 1},
+			existing: "https://new.example",
+			fresh:    true,
+		},
+		{
+			name:     "Fail",
+			opts:     transfer.ImportOptions{Strategy: transfer.StrategyFail},
+			report:   transfer.Report{Total: 2, Failed: 2, Conflicts: []string{"existing"}},
+			err:      transfer.ErrConflict,
+			existing: "https://old.example",
+		},
+		{
+			name:     "Dry run",
+			opts:     transfer.ImportOptions{Strategy: transfer.StrategyOverwrite, DryRun: true},
+			report:   transfer.Report{Total: 2, Created: 1, Updated: 1, DryRun: true},
+			existing: "https://old.example",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newStore(t, storage.Link{Alias: "existing", URL: "https://old.example"})
+
+			report, err := transfer.New(store, nil, nil).Import(context.Background(), strings.NewReader(input), transfer.FormatCSV, tc.opts)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.report, report)
+
+			u, err := store.GetURL(context.Background(), "existing")
+			require.NoError(t, err)
+			require.Equal(t, tc.existing, u)
+
+			_, err = store.GetURL(context.Background(), "fresh")
+			require.Equal(t, tc.fresh, err == nil)
+		})
+	}
+}
+
+func TestTransfer_ImportOverwriteUnavailable(t *testing.T) {
+	const input = "alias,url\ntrashed,https://new.example\nforeign,https://new.example\n"
+
+	store := newStore(t,
+		storage.Link{Alias: "trashed", URL: "https://old.example", OwnerID: 1},
+		storage.Link{Alias: "foreign", URL: "https://old.example", OwnerID: 2},
+	)
+	_, err := store.DeleteURL(context.Background(), "trashed")
+	require.NoError(t, err)
+
+	ctx := storage.WithCaller(context.Background(), storage.Caller{UserID: 1})
+	report, err := transfer.New(store, nil, nil).Import(ctx, strings.NewReader(input), transfer.FormatCSV, transfer.ImportOptions{Strategy: transfer.StrategyOverwrite})
+	require.NoError(t, err)
+	require.Equal(t, []transfer.RecordError{
+		{Line: 2, Alias: "trashed", Error: "alias is taken by a link in the trash"},
+		{Line: 3, Alias: "foreign", Error: "alias is taken by a link of another user"},
+	}, report.Errors)
+
+	u, err := store.GetURL(context.Background(), "foreign")
+	require.NoError(t, err)
+	require.Equal(t, "https://old.example", u)
+}
+
+func TestTransfer_ImportInvalidRecords(t *testing.T) {
+	input := strings.Join([]string{
+		`{"alias": "good", "url": "https://example.com"}`,
+		``,
+		`{"alias": "", "url": "https://example.com"}`,
+		`{"alias": "nohost", "url": "example.com"}`,
+		`{"alias": "good", "url": "https://example.org"}`,
+	}, "\n")
+
+	store := newStore(t)
+	report, err := transfer.New(store, nil, nil).Import(context.Background(), strings.NewReader(input), transfer.FormatNDJSON, transfer.ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Total)
+	require.Equal(t, 1, report.Created)
+	require.Equal(t, 3, report.Failed)
+	require.Equal(t, []transfer.RecordError{
+		{Line: 3, Error: "alias is required"},
+		{Line: 4, Alias: "nohost", Error: "url is not a valid URL"},
+		{Line: 5, Alias: "good", Error: "alias already used on line 1"},
+	}, report.Errors)
+}
+
+func TestTransfer_ImportDestinations(t *testing.T) {
+	input := strings.Join([]string{
+		`{"alias": "tracked", "url": "HTTPS://Example.com/a?utm_source=x&b=1"}`,
+		`{"alias": "local", "url": "http://127.0.0.1/admin"}`,
+		`{"alias": "ftp", "url": "ftp://example.com/file"}`,
+	}, "\n")
+
+	destinations, err := destination.NewPolicy(&config.DestinationConfig{Schemes: []string{"http", "https"}}, nil)
+	require.NoError(t, err)
+	canonicalizer := urlnorm.New(&config.URLConfig{StripTracking: true, TrackingParams: []string{"utm_source"}})
+
+	store := newStore(t)
+	report, err := transfer.New(store, canonicalizer, destinations).Import(context.Background(), strings.NewReader(input), transfer.FormatNDJSON, transfer.ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Created)
+	require.Equal(t, []transfer.RecordError{
+		{Line: 2, Alias: "local", Error: destination.ErrPrivate.Error()},
+		{Line: 3, Alias: "ftp", Error: destination.ErrScheme.Error()},
+	}, report.Errors)
+
+	origin, err := store.GetURL(context.Background(), "tracked")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/a?b=1", origin)
+}
+
+func TestTransfer_ImportMalformedFile(t *testing.T) {
+	cases := []struct {
+		name   string
+		format transfer.Format
+		input  string
+		line   int
+	}{
+		{name: "CSV without url column", format: transfer.FormatCSV, input: "alias,target\na,https://example.com\n", line: 1},
+		{name: "CSV bad timestamp", format: transfer.FormatCSV, input: "alias,url,created_at\na,https://example.com,\nb,https://example.com,yesterday\n", line: 3},
+		{name: "Broken JSON", format: transfer.FormatNDJSON, input: "{\"alias\": \"a\", \"url\": \"https://example.com\"}\n{oops\n", line: 2},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newStore(t)
+			_, err := transfer.New(store, nil, nil).Import(context.Background(), strings.NewReader(tc.input), tc.format, transfer.ImportOptions{})
+
+			var parseErr *transfer.ParseError
+			require.True(t, errors.As(err, &parseErr))
+			require.Equal(t, tc.line, parseErr.Line)
+
+			_, err = store.GetURL(context.Background(), "a")
+			require.ErrorIs(t, err, storage.ErrUrlNotFound)
+		})
+	}
+}
+
+func TestTransfer_ImportMaxRecords(t *testing.T) {
+	cases := []struct {
+		name   string
+		format transfer.Format
+		input  string
+	}{
+		{name: "CSV", format: transfer.FormatCSV, input: "alias,url\na,https://example.com/a\nb,https://example.com/b\nc,https://example.com/c\n"},
+		{name: "NDJSON", format: transfer.FormatNDJSON, input: "{\"alias\": \"a\", \"url\": \"https://example.com/a\"}\n{\"alias\": \"b\", \"url\": \"https://example.com/b\"}\n{\"alias\": \"c\", \"url\": \"https://example.com/c\"}\n"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newStore(t)
+			tr := transfer.New(store, nil, nil)
+
+			_, err := tr.Import(context.Background(), strings.NewReader(tc.input), tc.format, transfer.ImportOptions{MaxRecords: 2})
+			require.ErrorIs(t, err, transfer.ErrTooLarge)
+
+			_, err = store.GetURL(context.Background(), "a")
+			require.ErrorIs(t, err, storage.ErrUrlNotFound)
+
+			report, err := tr.Import(context.Background(), strings.NewReader(tc.input), tc.format, transfer.ImportOptions{MaxRecords: 3})
+			require.NoError(t, err)
+			require.Equal(t, 3, report.Created)
+		})
+	}
+}
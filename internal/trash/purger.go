@@ -0,0 +1,100 @@
+package trash
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl"
+)
+
+const purgeTimeout = time.Minute
+
+type DeletedPurger interface {
+	PurgeDeleted(ctx context.Context, before time.Time) ([]string, error)
+}
+
+// Purger permanently removes links that have been in the trash for longer
+// than the configured retention. It runs once on start and then on every
+// purge interval until closed, or only on start without a purge interval.
+//
+// A nil *Purger is valid and does nothing.
+type Purger struct {
+	log    *slog.Logger
+	purger DeletedPurger
+	cfg    *config.TrashConfig
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewPurger(log *slog.Logger, purger DeletedPurger, cfg *config.TrashConfig) *Purger {
+	p := &Purger{
+		log:    log.With(slog.String("component", "trash/purger")),
+		purger: purger,
+		cfg:    cfg,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	go p.run()
+
+	return p
+}
+
+// Close stops the purger and waits for a purge in progress to finish, or
+// until ctx is done.
+func (p *Purger) Close(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+
+	p.once.Do(func() { close(p.stop) })
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Purger) run() {
+	defer close(p.done)
+
+	if p.cfg.PurgeInterval <= 0 {
+		p.purge()
+		<-p.stop
+		return
+	}
+
+	ticker := time.NewTicker(p.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		p.purge()
+
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *Purger) purge() {
+	ctx, cancel := context.WithTimeout(context.Background(), purgeTimeout)
+	defer cancel()
+
+	aliases, err := p.purger.PurgeDeleted(ctx, time.Now().Add(-p.cfg.Retention))
+	if err != nil {
+		p.log.Error("failed to purge trash", sl.Err(err))
+		return
+	}
+
+	if len(aliases) > 0 {
+		p.log.Info("trash purged", slog.Int("count", len(aliases)), slog.Any("aliases", aliases))
+	}
+}
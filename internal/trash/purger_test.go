@@ -0,0 +1,69 @@
+package trash_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
+	"github.com/n0f4ph4mst3r/goshort/internal/trash"
+)
+
+type deletedPurger struct {
+	mu      sync.Mutex
+	cutoffs []time.Time
+}
+
+func (p *deletedPurger) PurgeDeleted(_ context.Context, before time.Time) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cutoffs = append(p.cutoffs, before)
+	return []string{"GoDuck"}, nil
+}
+
+func (p *deletedPurger) calls() []time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]time.Time(nil), p.cutoffs...)
+}
+
+func TestPurger_PurgesOnStartAndInterval(t *testing.T) {
+	deleted := &deletedPurger{}
+	start := time.Now()
+	purger := trash.NewPurger(sldiscard.NewDiscardLogger(), deleted, &config.TrashConfig{
+		Retention:     time.Hour,
+		PurgeInterval: 20 * time.Millisecond,
+	})
+
+	require.Eventually(t, func() bool { return len(deleted.calls()) >= 2 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, purger.Close(context.Background()))
+	require.NoError(t, purger.Close(context.Background()))
+
+	calls := deleted.calls()
+	for _, cutoff := range calls {
+		require.WithinDuration(t, start.Add(-time.Hour), cutoff, time.Second)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, deleted.calls(), len(calls))
+}
+
+func TestPurger_NoPurgeInterval(t *testing.T) {
+	deleted := &deletedPurger{}
+	purger := trash.NewPurger(sldiscard.NewDiscardLogger(), deleted, &config.TrashConfig{Retention: time.Hour})
+
+	require.Eventually(t, func() bool { return len(deleted.calls()) == 1 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, purger.Close(context.Background()))
+	require.Len(t, deleted.calls(), 1)
+}
+
+func TestPurger_NilIsNoop(t *testing.T) {
+	var purger *trash.Purger
+	require.NoError(t, purger.Close(context.Background()))
+}
@@ -0,0 +1,134 @@
+package urlnorm
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+)
+
+var ErrInvalidURL = errors.New("invalid URL")
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Canonicalizer rewrites destination URLs into a canonical form, so that
+// equivalent spellings of the same destination are stored once and found
+// by origin lookups.
+//
+// The scheme and host are lower-cased, internationalized host names are
+// converted to punycode, default ports are dropped and dot segments are
+// removed from the path. Sorting the query and stripping tracking
+// parameters change the URL more visibly and are enabled by configuration.
+// The zero Canonicalizer applies only the structural steps.
+type Canonicalizer struct {
+	sortQuery bool
+	tracking  map[string]struct{}
+}
+
+func New(cfg *config.URLConfig) *Canonicalizer {
+	c := &Canonicalizer{sortQuery: cfg.SortQuery}
+
+	if cfg.StripTracking {
+		c.tracking = make(map[string]struct{}, len(cfg.TrackingParams))
+		for _, param := range cfg.TrackingParams {
+			if param = strings.TrimSpace(param); param != "" {
+				c.tracking[strings.ToLower(param)] = struct{}{}
+			}
+		}
+	}
+
+	return c
+}
+
+func (c *Canonicalizer) Canonicalize(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", ErrInvalidURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	host := strings.ToLower(u.Hostname())
+	if ip := net.ParseIP(host); ip == nil {
+		if host, err = idna.Lookup.ToASCII(host); err != nil {
+			return "", ErrInvalidURL
+		}
+	} else if ip.To4() == nil {
+		host = "[" + host + "]"
+	}
+	if port := u.Port(); port != "" && port != defaultPorts[u.Scheme] {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), port)
+	}
+	u.Host = host
+
+	escaped := cleanPath(u.EscapedPath())
+	if u.Path, err = url.PathUnescape(escaped); err != nil {
+		return "", ErrInvalidURL
+	}
+	u.RawPath = escaped
+
+	u.RawQuery = c.query(u.RawQuery)
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// cleanPath removes dot segments and duplicate slashes while keeping a
+// trailing slash. An empty path becomes "/".
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+
+	return cleaned
+}
+
+// query drops tracking parameters and optionally sorts the rest by name.
+// Parameters are handled in their raw form so that their encoding and the
+// order of repeated values are preserved.
+func (c *Canonicalizer) query(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	var params []string
+	for _, param := range strings.Split(raw, "&") {
+		if param == "" {
+			continue
+		}
+		if _, ok := c.tracking[strings.ToLower(paramName(param))]; ok {
+			continue
+		}
+		params = append(params, param)
+	}
+
+	if c.sortQuery {
+		sort.SliceStable(params, func(i, j int) bool {
+			return paramName(params[i]) < paramName(params[j])
+		})
+	}
+
+	return strings.Join(params, "&")
+}
+
+func paramName(param string) string {
+	name, _, _ := strings.Cut(param, "=")
+	if unescaped, err := url.QueryUnescape(name); err == nil {
+		return unescaped
+	}
+	return name
+}
@@ -0,0 +1,55 @@
+package urlnorm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
+	"github.com/n0f4ph4mst3r/goshort/internal/urlnorm"
+)
+
+func TestCanonicalize(t *testing.T) {
+	full := urlnorm.New(&config.URLConfig{
+		SortQuery:      true,
+		StripTracking:  true,
+		TrackingParams: []string{"utm_source", "fbclid"},
+	})
+	structural := &urlnorm.Canonicalizer{}
+
+	cases := []struct {
+		name string
+		c    *urlnorm.Canonicalizer
+		in   string
+		out  string
+	}{
+		{"Scheme, host, port, path and query", full, "HTTP://Example.com:80/a/../b?b=2&a=1", "http://example.com/b?a=1&b=2"},
+		{"Already canonical", full, "http://example.com/b?a=1&b=2", "http://example.com/b?a=1&b=2"},
+		{"Default https port", full, "https://example.com:443", "https://example.com/"},
+		{"Custom port is kept", full, "https://example.com:8443/x", "https://example.com:8443/x"},
+		{"Dot segments and slashes", full, "https://example.com/a/./b//c/../d/", "https://example.com/a/b/d/"},
+		{"Path encoding is kept", full, "https://example.com/a%2Fb/c%20d", "https://example.com/a%2Fb/c%20d"},
+		{"IDN to punycode", full, "https://Bücher.example/", "https://xn--bcher-kva.example/"},
+		{"IPv6 with default port", full, "http://[::1]:80/", "http://[::1]/"},
+		{"Tracking parameters", full, "https://example.com/?UTM_Source=x&id=1&fbclid=y", "https://example.com/?id=1"},
+		{"Only tracking parameters", full, "https://example.com/p?utm_source=x", "https://example.com/p"},
+		{"Repeated values keep their order", full, "https://example.com/?b=2&a=3&a=1", "https://example.com/?a=3&a=1&b=2"},
+		{"Fragment is kept", full, "https://example.com/#Top", "https://example.com/#Top"},
+		{"Structural only", structural, "HTTPS://Example.com:443/a/../b?utm_source=x&b=2&a=1", "https://example.com/b?utm_source=x&b=2&a=1"},
+	}
+
+	for _, tc := range cases {
+		got, err := tc.c.Canonicalize(tc.in)
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.out, got, tc.name)
+	}
+}
+
+func TestCanonicalize_Invalid(t *testing.T) {
+	c := &urlnorm.Canonicalizer{}
+
+	for _, in := range []string{"", "example.com/path", "http://", "http://exa mple.com/", "http://xn--a.example/"} {
+		_, err := c.Canonicalize(in)
+		require.ErrorIs(t, err, urlnorm.ErrInvalidURL, in)
+	}
+}
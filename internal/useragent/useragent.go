@@ -0,0 +1,59 @@
+package useragent
+
+import "strings"
+
+const (
+	FamilyUnknown = "Unknown"
+	FamilyOther   = "Other"
+)
+
+var botMarkers = []string{"bot", "crawler", "spider", "slurp", "facebookexternalhit"}
+
+var families = []struct {
+	marker string
+	family string
+}{
+	{"edg/", "Edge"},
+	{"edge/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"yabrowser/", "Yandex Browser"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"chromium/", "Chrome"},
+	{"safari/", "Safari"},
+	{"msie ", "Internet Explorer"},
+	{"trident/", "Internet Explorer"},
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+	{"python-requests/", "Python Requests"},
+	{"go-http-client/", "Go HTTP Client"},
+}
+
+// Family reduces a User-Agent header to a coarse client family such as
+// "Chrome" or "Bot". The order of checks matters: most browsers carry the
+// tokens of the engines they derive from, so more specific markers go first.
+func Family(ua string) string {
+	if ua == "" {
+		return FamilyUnknown
+	}
+
+	lower := strings.ToLower(ua)
+
+	for _, marker := range botMarkers {
+		if strings.Contains(lower, marker) {
+			return "Bot"
+		}
+	}
+
+	for _, f := range families {
+		if strings.Contains(lower, f.marker) {
+			return f.family
+		}
+	}
+
+	return FamilyOther
+}
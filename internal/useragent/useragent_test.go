@@ -0,0 +1,67 @@
+package useragent_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/n0f4ph4mst3r/goshort/internal/useragent"
+)
+
+func TestFamily(t *testing.T) {
+	cases := []struct {
+		name     string
+		ua       string
+		expected string
+	}{
+		{
+			name:     "Empty",
+			ua:       "",
+			expected: useragent.FamilyUnknown,
+		},
+		{
+			name:     "Chrome",
+			ua:       "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected: "Chrome",
+		},
+		{
+			name:     "Edge",
+			ua:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			expected: "Edge",
+		},
+		{
+			name:     "Firefox",
+			ua:       "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			expected: "Firefox",
+		},
+		{
+			name:     "Safari",
+			ua:       "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+			expected: "Safari",
+		},
+		{
+			name:     "Bot",
+			ua:       "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expected: "Bot",
+		},
+		{
+			name:     "curl",
+			ua:       "curl/8.5.0",
+			expected: "curl",
+		},
+		{
+			name:     "Other",
+			ua:       "SomethingElse/1.0",
+			expected: useragent.FamilyOther,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expected, useragent.Family(tc.ua))
+		})
+	}
+}
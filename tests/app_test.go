@@ -1,27 +1,92 @@
 package tests
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v7"
 	"github.com/gavv/httpexpect/v2"
 	"github.com/stretchr/testify/require"
 
+	"github.com/n0f4ph4mst3r/goshort/internal/alias"
+	"github.com/n0f4ph4mst3r/goshort/internal/app"
+	"github.com/n0f4ph4mst3r/goshort/internal/auth"
+	"github.com/n0f4ph4mst3r/goshort/internal/config"
 	"github.com/n0f4ph4mst3r/goshort/internal/http-server/handlers/save"
+	"github.com/n0f4ph4mst3r/goshort/internal/jwt/jwttest"
+	"github.com/n0f4ph4mst3r/goshort/internal/sl/sldiscard"
 )
 
-const (
-	host = "localhost:8080"
-)
+func newTestServer(t *testing.T, opts ...func(cfg *config.Config)) *httptest.Server {
+	t.Helper()
 
-func TestGoShort_HappyPath(t *testing.T) {
-	u := url.URL{
-		Scheme: "http",
-		Host:   host,
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{
+			User:     "myuser",
+			Password: "qwerty",
+		},
+		Storage: config.StorageConfig{Backend: config.StorageMemory},
+		Auth:    config.AuthConfig{Mode: config.AuthKeys},
+		Cache: config.CacheConfig{
+			Enabled:         true,
+			Backend:         config.CacheMemory,
+			TTL:             time.Minute,
+			ReverseIndexTTL: time.Minute,
+			PrefixURL:       "url:",
+			PrefixRev:       "rev:",
+		},
+		RateLimit: config.RateLimitConfig{Backend: config.RateLimitMemory},
+		Analytics: config.AnalyticsConfig{
+			Enabled:       true,
+			BufferSize:    100,
+			BatchSize:     1,
+			FlushInterval: time.Second,
+			IPSalt:        "salt",
+		},
+		Alias: config.AliasConfig{
+			Policy: config.AliasPolicyConfig{
+				Charset:   "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_",
+				MinLength: 3,
+				MaxLength: 64,
+				Reserved:  []string{"api", "url", "trash", "alias", "admin", "static", "health"},
+			},
+		},
+		Destination: config.DestinationConfig{
+			Schemes:   []string{"http", "https"},
+			SelfHosts: []string{"sho.rt"},
+		},
+	}
+	for _, opt := range opts {
+		opt(cfg)
 	}
-	e := httpexpect.Default(t, u.String())
+
+	application, err := app.New(sldiscard.NewDiscardLogger(), cfg, "", "")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(application.Handler)
+	t.Cleanup(func() {
+		srv.Close()
+		require.NoError(t, application.Close(context.Background()))
+	})
+
+	return srv
+}
+
+func TestGoShort_HappyPath(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
 
 	e.POST("/api/url").
 		WithJSON(save.Request{
@@ -51,7 +116,7 @@ func TestGoShort_SaveRedirectDelete(t *testing.T) {
 		{
 			name:  "Invalid URL",
 			url:   "ht!tp://invalid-url",
-			alias: gofakeit.Word(),
+			alias: gofakeit.LetterN(10),
 			error: "field URL is not a valid URL",
 		},
 		{
@@ -71,13 +136,11 @@ func TestGoShort_SaveRedirectDelete(t *testing.T) {
 		},
 	}
 
+	srv := newTestServer(t)
+
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			u := url.URL{
-				Scheme: "http",
-				Host:   host,
-			}
-			e := httpexpect.Default(t, u.String())
+			e := httpexpect.Default(t, srv.URL)
 
 			if tc.name == "JSON decode error" {
 				e.POST("/api/url").
@@ -113,11 +176,8 @@ func TestGoShort_SaveRedirectDelete(t *testing.T) {
 				alias = resp_save.Value("alias").String().Raw()
 			}
 
-			redirectURL := url.URL{
-				Scheme: "http",
-				Host:   host,
-				Path:   "/api/url/" + alias,
-			}
+			redirectURL, err := url.JoinPath(srv.URL, "/api/url/", alias)
+			require.NoError(t, err)
 
 			client := &http.Client{
 				CheckRedirect: func(req *http.Request, via []*http.Request) error {
@@ -125,7 +185,7 @@ func TestGoShort_SaveRedirectDelete(t *testing.T) {
 				},
 			}
 
-			resp_redirect, err := client.Get(redirectURL.String())
+			resp_redirect, err := client.Get(redirectURL)
 			require.NoError(t, err)
 			defer resp_redirect.Body.Close()
 
@@ -142,11 +202,1198 @@ func TestGoShort_SaveRedirectDelete(t *testing.T) {
 				Expect().Status(http.StatusOK).JSON().Object().
 				Value("url").String().IsEqual(tc.url)
 
-			resp_redirect, err = client.Get(redirectURL.String())
+			resp_redirect, err = client.Get(redirectURL)
 			require.NoError(t, err)
 			defer resp_redirect.Body.Close()
 
-			require.Equal(t, http.StatusNotFound, resp_redirect.StatusCode)
+			require.Equal(t, http.StatusGone, resp_redirect.StatusCode)
+		})
+	}
+}
+
+func TestGoShort_DeleteKeepsSiblingAliases(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	origin := gofakeit.URL()
+	first := gofakeit.LetterN(10)
+	second := gofakeit.LetterN(10)
+
+	for _, alias := range []string{first, second} {
+		e.POST("/api/url").
+			WithJSON(save.Request{URL: origin, Alias: alias}).
+			WithBasicAuth("myuser", "qwerty").
+			Expect().
+			Status(http.StatusOK)
+	}
+
+	e.DELETE("/api/url/"+first).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK)
+
+	e.GET("/api/url/" + second).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusFound).
+		Header("Location").IsEqual(origin)
+
+	e.DELETE("/api/url").
+		WithQuery("origin", origin).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK).JSON().Object().
+		Value("aliases").Array().ConsistsOf(second)
+
+	e.GET("/api/url/" + second).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusGone)
+}
+
+func TestGoShort_TrashRestore(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	alias := gofakeit.LetterN(10)
+	origin := gofakeit.URL()
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Alias: alias}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/api/trash").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("links").Array().IsEmpty()
+
+	e.DELETE("/api/url/"+alias).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK)
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusGone)
+
+	e.DELETE("/api/url/"+alias).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusNotFound)
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Alias: alias}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusConflict)
+
+	trashed := e.GET("/api/trash").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("links").Array()
+	trashed.Length().IsEqual(1)
+	trashed.Value(0).Object().Value("alias").IsEqual(alias)
+	trashed.Value(0).Object().ContainsKey("deleted_at")
+
+	e.GET("/api/url").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("links").Array().IsEmpty()
+
+	e.POST("/api/url/"+alias+"/restore").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("url").IsEqual(origin)
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusFound).
+		Header("Location").IsEqual(origin)
+
+	e.POST("/api/url/"+alias+"/restore").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusNotFound)
+}
+
+func TestGoShort_UpdateDestination(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	alias := gofakeit.LetterN(10)
+	origin := gofakeit.URL()
+	moved := gofakeit.URL()
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Alias: alias}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusFound).
+		Header("Location").IsEqual(origin)
+
+	obj := e.PATCH("/api/url/"+alias).
+		WithJSON(map[string]string{"url": moved}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK).JSON().Object()
+	obj.Value("url").IsEqual(moved)
+	obj.Value("previous_url").IsEqual(origin)
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusFound).
+		Header("Location").IsEqual(moved)
+
+	e.PATCH("/api/url/"+gofakeit.LetterN(12)).
+		WithJSON(map[string]string{"url": moved}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusNotFound)
+}
+
+func TestGoShort_HistoryRollback(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	alias := gofakeit.LetterN(10)
+	origin := gofakeit.URL()
+	second := gofakeit.URL()
+	third := gofakeit.URL()
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Alias: alias}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	for _, u := range []string{second, third} {
+		e.PATCH("/api/url/"+alias).
+			WithJSON(map[string]string{"url": u}).
+			WithBasicAuth("myuser", "qwerty").
+			Expect().Status(http.StatusOK)
+	}
+
+	versions := e.GET("/api/url/"+alias+"/history").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("versions").Array()
+	versions.Length().IsEqual(2)
+	versions.Value(0).Object().Value("old_url").IsEqual(origin)
+	versions.Value(1).Object().Value("new_url").IsEqual(third)
+	versions.Value(1).Object().Value("changed_by").IsEqual("myuser")
+
+	e.POST("/api/url/"+alias+"/rollback").
+		WithJSON(map[string]int{"version": 0}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("version").IsEqual(3)
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusFound).
+		Header("Location").IsEqual(origin)
+
+	e.POST("/api/url/"+alias+"/rollback").
+		WithJSON(map[string]int{"version": 9}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusNotFound)
+
+	// A link that was never updated is still at version 0.
+	untouched := gofakeit.LetterN(10)
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Alias: untouched}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/api/url/"+untouched+"/rollback").
+		WithJSON(map[string]int{"version": 0}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("url").IsEqual(origin)
+}
+
+func TestGoShort_ReuseExistingAlias(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	origin := gofakeit.URL()
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, TTL: "1h"}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	first := e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Reuse: true}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	first.NotContainsKey("reused")
+	alias := first.Value("alias").String().Raw()
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Reuse: true}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("alias", alias).
+		HasValue("reused", true)
+
+	e.DELETE("/api/url/"+alias).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK)
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Reuse: true}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		NotContainsKey("reused").
+		Value("alias").NotEqual(alias)
+}
+
+func TestGoShort_SequenceAliases(t *testing.T) {
+	srv := newTestServer(t, func(cfg *config.Config) {
+		cfg.Alias = config.AliasConfig{Generator: config.AliasSequence, MinLength: 4, Secret: "secret"}
+	})
+	e := httpexpect.Default(t, srv.URL)
+
+	seen := make(map[string]struct{})
+	for range 5 {
+		origin := gofakeit.URL()
+		alias := e.POST("/api/url").
+			WithJSON(save.Request{URL: origin}).
+			WithBasicAuth("myuser", "qwerty").
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("alias").String().Raw()
+		require.Len(t, alias, 4)
+
+		require.NotContains(t, seen, alias)
+		seen[alias] = struct{}{}
+
+		e.GET("/api/url/" + alias).
+			WithRedirectPolicy(httpexpect.DontFollowRedirects).
+			Expect().Status(http.StatusFound).
+			Header("Location").IsEqual(origin)
+	}
+}
+
+func TestGoShort_ExpiredLinkIsGone(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	alias := gofakeit.LetterN(10)
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: gofakeit.URL(), Alias: alias, TTL: "200ms"}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		ContainsKey("expires_at")
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusFound)
+
+	time.Sleep(300 * time.Millisecond)
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusGone)
+}
+
+func TestGoShort_Stats(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	alias := gofakeit.LetterN(10)
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: gofakeit.URL(), Alias: alias}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	for range 3 {
+		e.GET("/api/url/"+alias).
+			WithRedirectPolicy(httpexpect.DontFollowRedirects).
+			WithHeader("Referer", "https://example.com").
+			WithHeader("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0").
+			Expect().Status(http.StatusFound)
+	}
+
+	require.Eventually(t, func() bool {
+		resp, err := http.NewRequest(http.MethodGet, srv.URL+"/api/url/"+alias+"/stats?interval=hour", nil)
+		require.NoError(t, err)
+		resp.SetBasicAuth("myuser", "qwerty")
+
+		res, err := http.DefaultClient.Do(resp)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		var body struct {
+			TotalClicks int `json:"total_clicks"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+
+		return body.TotalClicks == 3
+	}, time.Second, 10*time.Millisecond)
+
+	obj := e.GET("/api/url/"+alias+"/stats").
+		WithQuery("interval", "hour").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK).JSON().Object()
+
+	obj.Value("unique_visitors").Number().IsEqual(1)
+	obj.Value("buckets").Array().Length().Ge(24 * 7)
+	obj.Value("top_referrers").Array().Value(0).Object().Value("value").IsEqual("https://example.com")
+	obj.Value("user_agents").Array().Value(0).Object().Value("value").IsEqual("Firefox")
+
+	e.GET("/api/url/" + alias + "/stats").
+		Expect().Status(http.StatusUnauthorized)
+}
+
+func TestGoShort_ListPagination(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	var created []string
+	for i := range 5 {
+		alias := gofakeit.LetterN(10)
+		e.POST("/api/url").
+			WithJSON(save.Request{URL: "https://example.org/page/" + strconv.Itoa(i), Alias: alias}).
+			WithBasicAuth("myuser", "qwerty").
+			Expect().
+			Status(http.StatusOK)
+		created = append(created, alias)
+	}
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: "https://other.example/", Alias: gofakeit.LetterN(10)}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	var listed []string
+	cursor := ""
+	for {
+		req := e.GET("/api/url").
+			WithQuery("host", "example.org").
+			WithQuery("limit", 2).
+			WithQuery("order", "asc").
+			WithBasicAuth("myuser", "qwerty")
+		if cursor != "" {
+			req = req.WithQuery("cursor", cursor)
+		}
+
+		obj := req.Expect().Status(http.StatusOK).JSON().Object()
+		for _, link := range obj.Value("links").Array().Iter() {
+			listed = append(listed, link.Object().Value("alias").String().Raw())
+		}
+
+		next, ok := obj.Raw()["next_cursor"].(string)
+		if !ok {
+			break
+		}
+		cursor = next
+	}
+
+	require.Equal(t, created, listed)
+
+	e.GET("/api/url").
+		WithQuery("q", "OTHER.EXAMPLE").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().Status(http.StatusOK).JSON().Object().
+		Value("links").Array().Length().IsEqual(1)
+}
+
+func TestGoShort_AliasPolicy(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	testCases := []struct {
+		alias string
+		error string
+	}{
+		{alias: "Admin", error: "field Alias is a reserved word"},
+		{alias: "go/duck", error: "field Alias contains characters that are not allowed"},
+		{alias: "ab", error: "field Alias must be at least 3 characters long"},
+	}
+
+	for _, tc := range testCases {
+		e.POST("/api/url").
+			WithJSON(save.Request{URL: gofakeit.URL(), Alias: tc.alias}).
+			WithBasicAuth("myuser", "qwerty").
+			Expect().
+			Status(http.StatusBadRequest).
+			Body().Contains(tc.error)
+	}
+}
+
+func TestGoShort_CaseInsensitiveAliases(t *testing.T) {
+	srv := newTestServer(t, func(cfg *config.Config) {
+		cfg.Alias.CaseInsensitive = true
+	})
+	e := httpexpect.Default(t, srv.URL)
+
+	origin := gofakeit.URL()
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Alias: "GoDuck"}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	for _, typed := range []string{"GoDuck", "goduck", "GODUCK"} {
+		e.GET("/api/url/" + typed).
+			WithRedirectPolicy(httpexpect.DontFollowRedirects).
+			Expect().Status(http.StatusFound).
+			Header("Location").IsEqual(origin)
+	}
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: gofakeit.URL(), Alias: "goDUCK"}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusConflict)
+
+	for range 5 {
+		generated := e.POST("/api/url").
+			WithJSON(save.Request{URL: gofakeit.URL()}).
+			WithBasicAuth("myuser", "qwerty").
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("alias").String().Raw()
+		require.Len(t, generated, 7)
+		for _, c := range generated {
+			require.Contains(t, alias.Unambiguous, string(c))
+		}
+	}
+}
+
+func TestGoShort_SuggestAliases(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	base := gofakeit.LetterN(8)
+	for _, taken := range []string{base, base + "1"} {
+		e.POST("/api/url").
+			WithJSON(save.Request{URL: gofakeit.URL(), Alias: taken}).
+			WithBasicAuth("myuser", "qwerty").
+			Expect().
+			Status(http.StatusOK)
+	}
+
+	suggestions := e.GET("/api/alias/suggest").
+		WithQuery("base", base).
+		WithQuery("limit", 3).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("suggestions").Array()
+	suggestions.IsEqual([]string{base + "-1", base + "_1", base + "-go"})
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: gofakeit.URL(), Alias: base + "-1"}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/api/alias/suggest").
+		WithQuery("base", base).
+		WithQuery("limit", 1).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("suggestions").Array().IsEqual([]string{base + "_1"})
+
+	e.GET("/api/alias/suggest").
+		WithQuery("base", base).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestGoShort_CanonicalOrigins(t *testing.T) {
+	srv := newTestServer(t, func(cfg *config.Config) {
+		cfg.URL = config.URLConfig{SortQuery: true, StripTracking: true, TrackingParams: []string{"utm_source"}}
+	})
+	e := httpexpect.Default(t, srv.URL)
+
+	host := strings.ToLower(gofakeit.LetterN(10)) + ".example"
+	canonical := "http://" + host + "/b?a=1&b=2"
+
+	first := e.POST("/api/url").
+		WithJSON(save.Request{URL: "HTTP://" + strings.ToUpper(host) + ":80/a/../b?b=2&a=1&utm_source=mail", Reuse: true}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	first.Value("url").IsEqual(canonical)
+	first.NotContainsKey("reused")
+	alias := first.Value("alias").String().Raw()
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: canonical, Reuse: true}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("alias", alias).
+		HasValue("reused", true)
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusFound).
+		Header("Location").IsEqual(canonical)
+
+	e.DELETE("/api/url").
+		WithQuery("origin", "http://"+host+":80/b/./?b=2&a=1").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.DELETE("/api/url").
+		WithQuery("origin", "http://"+host+":80/b?b=2&a=1").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("aliases").Array().IsEqual([]string{alias})
+}
+
+func TestGoShort_DestinationPolicy(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	testCases := []struct {
+		url   string
+		error string
+	}{
+		{url: "javascript:alert(1)", error: "destination scheme is not allowed"},
+		{url: "file:///etc/passwd", error: "destination scheme is not allowed"},
+		{url: "http://127.0.0.1/admin", error: "destination points to a private or loopback address"},
+		{url: "https://sho.rt/" + gofakeit.LetterN(6), error: "destination points back to this shortener"},
+	}
+
+	for _, tc := range testCases {
+		e.POST("/api/url").
+			WithJSON(save.Request{URL: tc.url}).
+			WithBasicAuth("myuser", "qwerty").
+			Expect().
+			Status(http.StatusUnprocessableEntity).
+			Body().Contains(tc.error)
+	}
+
+	alias := gofakeit.LetterN(10)
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: gofakeit.URL(), Alias: alias}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	e.PATCH("/api/url/"+alias).
+		WithJSON(map[string]string{"url": "http://169.254.169.254/latest/meta-data/"}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		Body().Contains("destination points to a private or loopback address")
+}
+
+func TestGoShort_Blocklist(t *testing.T) {
+	dir := t.TempDir()
+	domains := filepath.Join(dir, "domains.txt")
+	prefixes := filepath.Join(dir, "prefixes.txt")
+	require.NoError(t, os.WriteFile(domains, []byte("# bad domains\n"), 0o600))
+
+	phish := sha256.Sum256([]byte("shared.example/phish/"))
+	require.NoError(t, os.WriteFile(prefixes, []byte(hex.EncodeToString(phish[:4])+"\n"), 0o600))
+
+	srv := newTestServer(t, func(cfg *config.Config) {
+		cfg.Destination.Blocklist = config.BlocklistConfig{
+			DomainsFile:     domains,
+			HashPrefixFile:  prefixes,
+			RefreshInterval: 20 * time.Millisecond,
+		}
+	})
+	e := httpexpect.Default(t, srv.URL)
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: "https://shared.example/phish/login.html?x=1"}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		Body().Contains("destination URL is on the blocklist")
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: "https://shared.example/docs/"}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	// A stored link whose domain is blocklisted later gets the warning
+	// page once the list is refreshed, and is flagged in storage.
+	alias := gofakeit.LetterN(10)
+	origin := "https://turned-bad.example/page"
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Alias: alias}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	require.NoError(t, os.WriteFile(domains, []byte("turned-bad.example\n"), 0o600))
+
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(srv.URL + "/api/url/" + alias)
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusForbidden
+	}, 2*time.Second, 20*time.Millisecond)
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().
+		Status(http.StatusForbidden).
+		ContentType("text/html").
+		Body().Contains("destination domain is on the blocklist")
+
+	link := e.GET("/api/url").
+		WithQuery("q", "turned-bad.example").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("links").Array().Value(0).Object()
+	link.HasValue("alias", alias)
+	link.HasValue("flag_reason", "destination domain is on the blocklist")
+	link.ContainsKey("flagged_at")
+}
+
+func TestGoShort_Batch(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	taken := gofakeit.LetterN(10)
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: gofakeit.URL(), Alias: taken}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	named := gofakeit.LetterN(10)
+	resp := e.POST("/api/url/batch").
+		WithJSON([]save.Request{
+			{URL: "https://example.com/one", Alias: named},
+			{URL: "https://example.com/two"},
+			{URL: "https://example.com/three", Alias: taken},
+			{URL: "javascript:alert(1)"},
+			{URL: "https://example.com/four", Alias: named},
+		}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resp.HasValue("saved", 2).HasValue("failed", 3)
+
+	results := resp.Value("results").Array()
+	results.Value(0).Object().HasValue("status", http.StatusOK).HasValue("alias", named)
+	generated := results.Value(1).Object().HasValue("status", http.StatusOK).Value("alias").String().NotEmpty().Raw()
+	results.Value(2).Object().HasValue("status", http.StatusConflict).HasValue("error", "alias already exists")
+	results.Value(3).Object().HasValue("status", http.StatusUnprocessableEntity).HasValue("error", "destination scheme is not allowed")
+	results.Value(4).Object().HasValue("status", http.StatusConflict)
+
+	e.GET("/api/url/" + generated).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusFound).
+		Header("Location").IsEqual("https://example.com/two")
+
+	ndjson := e.POST("/api/url/batch").
+		WithHeader("Content-Type", "application/x-ndjson").
+		WithText("{\"url\": \"https://example.com/five\"}\n{\"url\": \"https://example.com/six\"}\n").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	ndjson.HasValue("saved", 2).HasValue("failed", 0)
+}
+
+func TestGoShort_Backup(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	alias := gofakeit.LetterN(10)
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: "https://example.com/exported", Alias: alias}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/api/backup/export").
+		WithQuery("format", "csv").
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	export := e.GET("/api/backup/export").
+		WithQuery("format", "csv").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+	export.Header("Content-Type").IsEqual("text/csv")
+	body := export.Body().Contains("alias,url,created_at").Contains(alias + ",https://example.com/exported,").Raw()
+
+	e.POST("/api/backup/import").
+		WithQuery("strategy", "fail").
+		WithHeader("Content-Type", "text/csv").
+		WithText(body).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusConflict).
+		Body().Contains(alias)
+
+	fresh := gofakeit.LetterN(10)
+	input := fmt.Sprintf("{\"alias\": %q, \"url\": \"https://example.com/imported\"}\n{\"alias\": %q, \"url\": \"https://example.com/changed\"}\n", fresh, alias)
+
+	e.POST("/api/backup/import").
+		WithQuery("strategy", "overwrite").
+		WithQuery("dry_run", "true").
+		WithHeader("Content-Type", "application/x-ndjson").
+		WithText(input).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("created", 1).
+		HasValue("updated", 1).
+		HasValue("dry_run", true)
+
+	e.GET("/api/url/" + fresh).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.POST("/api/backup/import").
+		WithQuery("strategy", "overwrite").
+		WithHeader("Content-Type", "application/x-ndjson").
+		WithText(input).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("created", 1).
+		HasValue("updated", 1)
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusFound).
+		Header("Location").IsEqual("https://example.com/changed")
+
+	e.GET("/api/url/" + fresh).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().Status(http.StatusFound).
+		Header("Location").IsEqual("https://example.com/imported")
+}
+
+func TestGoShort_APIKeys(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	e.GET("/api/url").
+		Expect().
+		Status(http.StatusUnauthorized).
+		Header("WWW-Authenticate").Contains("Bearer")
+
+	e.GET("/api/url").
+		WithHeader("Authorization", "Bearer gs_doesnotexist").
+		Expect().
+		Status(http.StatusUnauthorized).
+		Body().Contains("invalid API key")
+
+	name := strings.ToLower(gofakeit.LetterN(8))
+	e.POST("/api/users").
+		WithJSON(map[string]string{"name": name}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("user").Object().HasValue("name", name)
+
+	e.POST("/api/users").
+		WithJSON(map[string]string{"name": name}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusConflict)
+
+	issued := e.POST("/api/users/"+name+"/keys").
+		WithJSON(map[string]any{"name": "ci", "scopes": []string{"links:write", "links:read"}}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	key := issued.Value("key").String().HasPrefix("gs_").Raw()
+	apiKey := issued.Value("api_key").Object()
+	apiKey.HasValue("user", name).HasValue("name", "ci")
+	apiKey.Value("scopes").Array().IsEqual([]string{"links:read", "links:write"})
+	apiKey.Value("prefix").String().IsEqual(key[:11])
+	apiKey.NotContainsKey("last_used_at")
+	id := int64(apiKey.Value("id").Number().Raw())
+
+	alias := gofakeit.LetterN(10)
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: gofakeit.URL(), Alias: alias}).
+		WithHeader("Authorization", "Bearer "+key).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/api/users/"+name+"/keys").
+		WithHeader("Authorization", "Bearer "+key).
+		Expect().
+		Status(http.StatusForbidden).
+		Body().Contains("missing scope admin")
+
+	keys := e.GET("/api/users/"+name+"/keys").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("keys").Array()
+	keys.Length().IsEqual(1)
+	keys.Value(0).Object().ContainsKey("last_used_at").NotContainsKey("key")
+
+	e.DELETE(fmt.Sprintf("/api/keys/%d", id)).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK)
+
+	e.DELETE(fmt.Sprintf("/api/keys/%d", id)).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.DELETE("/api/url/"+alias).
+		WithHeader("Authorization", "Bearer "+key).
+		Expect().
+		Status(http.StatusUnauthorized).
+		Body().Contains("API key has been revoked")
+}
+
+// issueKey creates a user with the BasicAuth pair and returns its id and
+// a fresh API key with the given scopes.
+func issueKey(e *httpexpect.Expect, name string, scopes ...string) (int64, string) {
+	user := e.POST("/api/users").
+		WithJSON(map[string]string{"name": name}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("user").Object()
+
+	key := e.POST("/api/users/"+name+"/keys").
+		WithJSON(map[string]any{"name": "test", "scopes": scopes}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("key").String().Raw()
+
+	return int64(user.Value("id").Number().Raw()), key
+}
+
+func TestGoShort_Ownership(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	aliceID, alice := issueKey(e, strings.ToLower(gofakeit.LetterN(8)))
+	_, bob := issueKey(e, strings.ToLower(gofakeit.LetterN(8)))
+
+	origin := gofakeit.URL()
+	alias := gofakeit.LetterN(10)
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Alias: alias}).
+		WithHeader("Authorization", "Bearer "+alice).
+		Expect().
+		Status(http.StatusOK)
+
+	e.DELETE("/api/url/"+alias).
+		WithHeader("Authorization", "Bearer "+bob).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.PATCH("/api/url/"+alias).
+		WithJSON(map[string]string{"url": gofakeit.URL()}).
+		WithHeader("Authorization", "Bearer "+bob).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.GET("/api/url/"+alias+"/stats").
+		WithHeader("Authorization", "Bearer "+bob).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.GET("/api/url").
+		WithHeader("Authorization", "Bearer "+bob).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("links").Array().IsEmpty()
+
+	bobs := e.POST("/api/url").
+		WithJSON(save.Request{URL: origin, Reuse: true}).
+		WithHeader("Authorization", "Bearer "+bob).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		NotContainsKey("reused").
+		Value("alias").String().NotEqual(alias).Raw()
+
+	e.DELETE("/api/url").
+		WithQuery("origin", origin).
+		WithHeader("Authorization", "Bearer "+bob).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("aliases").Array().ConsistsOf(bobs)
+
+	own := e.GET("/api/url").
+		WithHeader("Authorization", "Bearer "+alice).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("links").Array()
+	own.Length().IsEqual(1)
+	own.Value(0).Object().HasValue("alias", alias).HasValue("owner_id", aliceID)
+
+	e.GET("/api/url").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("links").Array().Length().IsEqual(1)
+
+	e.DELETE("/api/url/"+alias).
+		WithHeader("Authorization", "Bearer "+alice).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().
+		Status(http.StatusGone)
+}
+
+func TestGoShort_Scopes(t *testing.T) {
+	srv := newTestServer(t)
+	e := httpexpect.Default(t, srv.URL)
+
+	_, writer := issueKey(e, strings.ToLower(gofakeit.LetterN(8)), auth.ScopeLinksRead, auth.ScopeLinksWrite)
+	_, admin := issueKey(e, strings.ToLower(gofakeit.LetterN(8)), auth.ScopeAdmin)
+
+	e.POST("/api/users/nobody/keys").
+		WithJSON(map[string]any{"scopes": []string{"links:everything"}}).
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusBadRequest).
+		Body().Contains(`unknown scope \"links:everything\"`)
+
+	alias := gofakeit.LetterN(10)
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: gofakeit.URL(), Alias: alias}).
+		WithHeader("Authorization", "Bearer "+writer).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/api/url/"+alias+"/stats").
+		WithHeader("Authorization", "Bearer "+writer).
+		Expect().
+		Status(http.StatusOK)
+
+	e.DELETE("/api/url/"+alias).
+		WithHeader("Authorization", "Bearer "+writer).
+		Expect().
+		Status(http.StatusForbidden).
+		Header("WWW-Authenticate").Contains(`scope="links:delete"`)
+
+	e.POST("/api/users").
+		WithJSON(map[string]string{"name": gofakeit.LetterN(8)}).
+		WithHeader("Authorization", "Bearer "+writer).
+		Expect().
+		Status(http.StatusForbidden).
+		Body().Contains("missing scope admin")
+
+	e.DELETE("/api/url/"+alias).
+		WithHeader("Authorization", "Bearer "+admin).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/api/users").
+		WithHeader("Authorization", "Bearer "+admin).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("users").Array().Length().IsEqual(2)
+}
+
+func TestGoShort_JWT(t *testing.T) {
+	signer := jwttest.NewES256("test-key")
+	jwks := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwks, jwttest.JWKS(signer), 0o600))
+
+	srv := newTestServer(t, func(cfg *config.Config) {
+		cfg.Auth = config.AuthConfig{
+			Mode: config.AuthJWT,
+			JWT: config.JWTConfig{
+				JWKSFile:    jwks,
+				Issuer:      "https://id.example",
+				Audience:    "goshort",
+				Leeway:      time.Minute,
+				UserClaim:   "sub",
+				ScopesClaim: "scope",
+			},
+		}
+	})
+	e := httpexpect.Default(t, srv.URL)
+
+	token := func(sub, scope string, exp time.Time) string {
+		return signer.Sign(map[string]any{
+			"iss":   "https://id.example",
+			"aud":   "goshort",
+			"sub":   sub,
+			"scope": scope,
+			"exp":   exp.Unix(),
 		})
 	}
+	alice := token("alice", "openid links:read links:write", time.Now().Add(time.Hour))
+	bob := token("bob", "links:read", time.Now().Add(time.Hour))
+
+	alias := gofakeit.LetterN(10)
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: gofakeit.URL(), Alias: alias}).
+		WithHeader("Authorization", "Bearer "+alice).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/api/url").
+		WithHeader("Authorization", "Bearer "+alice).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("links").Array().Length().IsEqual(1)
+
+	e.GET("/api/url").
+		WithHeader("Authorization", "Bearer "+bob).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("links").Array().IsEmpty()
+
+	e.DELETE("/api/url/"+alias).
+		WithHeader("Authorization", "Bearer "+alice).
+		Expect().
+		Status(http.StatusForbidden)
+
+	e.GET("/api/url").
+		WithHeader("Authorization", "Bearer "+token("alice", "links:read", time.Now().Add(-time.Hour))).
+		Expect().
+		Status(http.StatusUnauthorized).
+		Body().Contains("token has expired")
+
+	e.GET("/api/url").
+		WithHeader("Authorization", "Bearer "+jwttest.NewES256("test-key").Sign(map[string]any{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})).
+		Expect().
+		Status(http.StatusUnauthorized).
+		Body().Contains("invalid token signature")
+
+	e.GET("/api/url").
+		WithBasicAuth("myuser", "qwerty").
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	e.GET("/api/url/" + alias).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().
+		Status(http.StatusFound)
+}
+
+func TestGoShort_RateLimit(t *testing.T) {
+	srv := newTestServer(t, func(cfg *config.Config) {
+		cfg.RateLimit = config.RateLimitConfig{
+			Enabled:  true,
+			Backend:  config.RateLimitMemory,
+			Create:   config.RateLimit{Rate: 2, Period: time.Minute},
+			Delete:   config.RateLimit{Rate: 1, Period: 200 * time.Millisecond},
+			Redirect: config.RateLimit{Rate: 3, Period: time.Minute},
+			Bulk:     config.RateLimit{Rate: 1, Period: time.Minute},
+		}
+	})
+	e := httpexpect.Default(t, srv.URL)
+
+	_, alice := issueKey(e, strings.ToLower(gofakeit.LetterN(8)))
+	_, bob := issueKey(e, strings.ToLower(gofakeit.LetterN(8)))
+	_, carol := issueKey(e, strings.ToLower(gofakeit.LetterN(8)))
+
+	aliases := make([]string, 2)
+	for i := range aliases {
+		aliases[i] = gofakeit.LetterN(10)
+		e.POST("/api/url").
+			WithJSON(save.Request{URL: gofakeit.URL(), Alias: aliases[i]}).
+			WithHeader("Authorization", "Bearer "+alice).
+			Expect().
+			Status(http.StatusOK)
+	}
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: gofakeit.URL()}).
+		WithHeader("Authorization", "Bearer "+alice).
+		Expect().
+		Status(http.StatusTooManyRequests).
+		Header("Retry-After").IsEqual("30")
+
+	e.POST("/api/url").
+		WithJSON(save.Request{URL: gofakeit.URL()}).
+		WithHeader("Authorization", "Bearer "+bob).
+		Expect().
+		Status(http.StatusOK)
+
+	for range 3 {
+		e.GET("/api/url/" + aliases[0]).
+			WithRedirectPolicy(httpexpect.DontFollowRedirects).
+			Expect().
+			Status(http.StatusFound)
+	}
+	e.GET("/api/url/" + aliases[0]).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().
+		Status(http.StatusTooManyRequests).
+		Body().Contains("rate limit exceeded")
+
+	e.DELETE("/api/url/"+aliases[0]).
+		WithHeader("Authorization", "Bearer "+alice).
+		Expect().
+		Status(http.StatusOK)
+
+	e.DELETE("/api/url/"+aliases[1]).
+		WithHeader("Authorization", "Bearer "+alice).
+		Expect().
+		Status(http.StatusTooManyRequests).
+		Header("Retry-After").IsEqual("1")
+
+	time.Sleep(250 * time.Millisecond)
+
+	e.DELETE("/api/url/"+aliases[1]).
+		WithHeader("Authorization", "Bearer "+alice).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/api/url/batch").
+		WithJSON([]save.Request{{URL: gofakeit.URL()}, {URL: gofakeit.URL()}, {URL: gofakeit.URL()}}).
+		WithHeader("Authorization", "Bearer "+carol).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/api/backup/import").
+		WithHeader("Content-Type", "application/x-ndjson").
+		WithText(fmt.Sprintf("{\"alias\": %q, \"url\": \"https://example.com/imported\"}\n", gofakeit.LetterN(10))).
+		WithHeader("Authorization", "Bearer "+carol).
+		Expect().
+		Status(http.StatusTooManyRequests)
 }